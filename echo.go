@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// keystrokes typed at the terminal, read as soon as they're typed. the
+// terminal doesn't echo anything itself, so everything shows up exactly when
+// it's written back out.
+//
+// typing ^C or ^D stops reading. return starts a new line, and backspace
+// erases.
+type keystrokes struct {
+	fd      int
+	state   *term.State
+	pending []byte
+	done    bool
+}
+
+func startEcho() (*keystrokes, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, errors.New("echoing what's typed needs a terminal")
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	return &keystrokes{fd: fd, state: state}, nil
+}
+
+func (k *keystrokes) Read(p []byte) (int, error) {
+	for len(k.pending) == 0 {
+		if k.done {
+			return 0, io.EOF
+		}
+
+		buf := make([]byte, len(p))
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return 0, err
+		}
+		k.pending = k.translate(buf[:n])
+	}
+
+	n := copy(p, k.pending)
+	k.pending = k.pending[n:]
+	return n, nil
+}
+
+// a raw terminal doesn't do anything for anyone, so do it here
+func (k *keystrokes) translate(typed []byte) []byte {
+	var out bytes.Buffer
+	for _, b := range typed {
+		switch b {
+		case 0x03, 0x04:
+			k.done = true
+			return out.Bytes()
+		case '\r':
+			out.WriteString("\r\n")
+		case 0x7f:
+			out.WriteString("\b \b")
+		default:
+			out.WriteByte(b)
+		}
+	}
+	return out.Bytes()
+}
+
+// put the terminal back the way it was
+func (k *keystrokes) Close() error {
+	return term.Restore(k.fd, k.state)
+}
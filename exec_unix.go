@@ -0,0 +1,97 @@
+//go:build !windows
+// +build !windows
+
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"github.com/creack/pty"
+	"golang.org/x/term"
+)
+
+// a command running on a pseudo-terminal. reading from it reads whatever the
+// command writes, and everything typed at the real terminal goes straight
+// through to the command without waiting.
+type child struct {
+	cmd     *exec.Cmd
+	pty     *os.File
+	restore func()
+}
+
+func startCommand(args []string) (*child, error) {
+	cmd := exec.Command(args[0], args[1:]...)
+	ptmx, err := pty.Start(cmd)
+	if err != nil {
+		return nil, err
+	}
+	c := &child{cmd: cmd, pty: ptmx, restore: func() {}}
+
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		// keep the command's terminal the same size as ours
+		resized := make(chan os.Signal, 1)
+		signal.Notify(resized, syscall.SIGWINCH)
+		go func() {
+			for range resized {
+				pty.InheritSize(os.Stdin, ptmx)
+			}
+		}()
+		resized <- syscall.SIGWINCH
+
+		// every keystroke goes through as soon as it's typed
+		state, err := term.MakeRaw(int(os.Stdin.Fd()))
+		if err == nil {
+			c.restore = func() {
+				signal.Stop(resized)
+				term.Restore(int(os.Stdin.Fd()), state)
+			}
+		}
+	}
+	go io.Copy(ptmx, os.Stdin)
+
+	return c, nil
+}
+
+func (c *child) Read(p []byte) (int, error) {
+	n, err := c.pty.Read(p)
+	// once the command is gone, reading its terminal fails instead of ending
+	if errors.Is(err, syscall.EIO) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// put the terminal back the way it was and wait for the command to finish
+func (c *child) Close() error {
+	c.restore()
+	err := c.cmd.Wait()
+	c.pty.Close()
+	return err
+}
+
+// stop the command early, when there's no point writing any more of what it
+// writes
+func (c *child) Kill() error {
+	return c.cmd.Process.Kill()
+}
+
+// the status to exit with after the command exits with err. commands killed
+// by a signal exit like a shell would say they did.
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		if err != nil {
+			return 1
+		}
+		return 0
+	}
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+	return exitErr.ExitCode()
+}
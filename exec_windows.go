@@ -0,0 +1,31 @@
+package main
+
+import (
+	"errors"
+	"io"
+)
+
+type child struct{}
+
+func startCommand(args []string) (*child, error) {
+	return nil, errors.New("running commands isn't supported on windows")
+}
+
+func (c *child) Read(p []byte) (int, error) {
+	return 0, io.EOF
+}
+
+func (c *child) Close() error {
+	return nil
+}
+
+func (c *child) Kill() error {
+	return nil
+}
+
+func exitCode(err error) int {
+	if err != nil {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"golang.org/x/text/transform"
+)
+
+// a finger server (RFC 1288) where everyone has the same plan, and it's the
+// show
+func serveFinger(ctx context.Context, l net.Listener, sh *show) error {
+	return each(ctx, l, func(ctx context.Context, conn net.Conn) {
+		conn.SetReadDeadline(time.Now().Add(requestTimeout))
+		query, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			return
+		}
+		conn.SetReadDeadline(time.Time{})
+
+		// /W only asks for more detail, and there isn't any
+		query = strings.TrimSpace(query)
+		query = strings.TrimSpace(strings.TrimPrefix(query, "/W"))
+		if strings.ContainsRune(query, '@') {
+			fmt.Fprint(conn, "fingering anyone somewhere else isn't allowed here\r\n")
+			return
+		}
+
+		user := query
+		if user == "" {
+			user = "everyone"
+		}
+		if _, err := fmt.Fprintf(conn, "Login: %s\r\nPlan:\r\n", user); err != nil {
+			return
+		}
+
+		w := transform.NewWriter(conn, transform.Chain(lfOnly{}, crlf{}))
+		if sh.play(ctx, w, nil) == nil {
+			w.Close()
+		}
+	})
+}
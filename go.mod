@@ -0,0 +1,15 @@
+module github.com/blinsay/aslap
+
+go 1.26.0
+
+require (
+	github.com/creack/pty v1.1.24
+	github.com/fsnotify/fsnotify v1.10.1
+	github.com/rivo/uniseg v0.4.7
+	go.bug.st/serial v1.8.0
+	golang.org/x/crypto v0.57.0
+	golang.org/x/term v0.46.0
+	golang.org/x/text v0.42.0
+)
+
+require golang.org/x/sys v0.48.0 // indirect
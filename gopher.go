@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/text/transform"
+)
+
+// a gopher server (RFC 1436) with a menu of everything in the show. the menu
+// comes right away, and every document is as slow as possible.
+func serveGopher(ctx context.Context, l net.Listener, sh *show) error {
+	return each(ctx, l, func(ctx context.Context, conn net.Conn) {
+		conn.SetReadDeadline(time.Now().Add(requestTimeout))
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			return
+		}
+		conn.SetReadDeadline(time.Time{})
+
+		// anything after a tab is a search, and there's nothing to search
+		selector := strings.TrimRight(line, "\r\n")
+		if i := strings.IndexByte(selector, '\t'); i >= 0 {
+			selector = selector[:i]
+		}
+
+		docs := gopherDocuments(sh)
+		if selector == "" || selector == "/" {
+			gopherMenu(conn, docs)
+			return
+		}
+		doc, ok := docs[selector]
+		if !ok {
+			fmt.Fprintf(conn, "3there's nothing here called %s\t\terror.host\t1\r\n.\r\n", selector)
+			return
+		}
+
+		text := &dotted{}
+		w := transform.NewWriter(conn, transform.Chain(lfOnly{}, crlf{}, text))
+		if err := doc.play(ctx, w, nil); err != nil {
+			return
+		}
+		w.Close()
+		if text.mid {
+			io.WriteString(conn, "\r\n")
+		}
+		io.WriteString(conn, ".\r\n")
+	})
+}
+
+// every document in the show by its selector. files are documents of their
+// own, and anything piped in is one document.
+func gopherDocuments(sh *show) map[string]*show {
+	docs := map[string]*show{}
+	if sh.names == nil {
+		docs["/show"] = sh
+		return docs
+	}
+	for _, name := range sh.names {
+		doc := *sh
+		doc.names = []string{name}
+		docs["/"+strings.TrimPrefix(filepath.ToSlash(name), "/")] = &doc
+	}
+	return docs
+}
+
+// a menu of every document, pointing back at wherever conn was connected to
+func gopherMenu(conn net.Conn, docs map[string]*show) {
+	host, port, err := net.SplitHostPort(conn.LocalAddr().String())
+	if err != nil {
+		host, port = "localhost", "70"
+	}
+
+	menu := bufio.NewWriter(conn)
+	fmt.Fprintf(menu, "ias slow as possible\t\t%s\t%s\r\n", host, port)
+	for _, selector := range selectors(docs) {
+		fmt.Fprintf(menu, "0%s\t%s\t%s\t%s\r\n", strings.TrimPrefix(selector, "/"), selector, host, port)
+	}
+	menu.WriteString(".\r\n")
+	menu.Flush()
+}
+
+func selectors(docs map[string]*show) []string {
+	var keys []string
+	for k := range docs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// a line of text that starts with a dot gets another one, so it can't be
+// mistaken for the end of the document. mid is whether the last line written
+// hasn't ended yet.
+type dotted struct {
+	mid bool
+}
+
+func (d *dotted) Reset() {
+	d.mid = false
+}
+
+func (d *dotted) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for ; nSrc < len(src); nSrc++ {
+		b := src[nSrc]
+		need := 1
+		if b == '.' && !d.mid {
+			need = 2
+		}
+		if nDst+need > len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		if need == 2 {
+			dst[nDst] = '.'
+			nDst++
+		}
+		dst[nDst] = b
+		nDst++
+		d.mid = b != '\n'
+	}
+	return nDst, nSrc, nil
+}
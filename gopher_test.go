@@ -0,0 +1,77 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"golang.org/x/text/transform"
+)
+
+func TestDotted(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"nothing", "", ""},
+		{"no dots", "hi\nthere\n", "hi\nthere\n"},
+		{"first line", ".hi\n", "..hi\n"},
+		{"later line", "hi\n.there\n", "hi\n..there\n"},
+		{"just a dot", "a\n.\n", "a\n..\n"},
+		// only the start of the line counts
+		{"mid line", "a.b\n", "a.b\n"},
+		{"dots in a row", "..\n", "...\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := transform.String(&dotted{}, tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+
+			// where the writes are split doesn't matter
+			d := &dotted{}
+			r := transform.NewReader(iotest.OneByteReader(strings.NewReader(tt.input)), d)
+			split, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(split) != tt.want {
+				t.Errorf("a byte at a time got %q, want %q", split, tt.want)
+			}
+		})
+	}
+}
+
+func TestGopherDocuments(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		want  []string
+	}{
+		// anything piped in is one document
+		{"stdin", nil, []string{"/show"}},
+		{"files", []string{"b.txt", "a.txt"}, []string{"/a.txt", "/b.txt"}},
+		{"paths", []string{"/etc/motd", "docs/x.txt"}, []string{"/docs/x.txt", "/etc/motd"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs := gopherDocuments(&show{names: tt.names})
+			if got := selectors(docs); strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("got selectors %q, want %q", got, tt.want)
+			}
+			for selector, doc := range docs {
+				if tt.names != nil && (len(doc.names) != 1 || "/"+strings.TrimPrefix(doc.names[0], "/") != selector) {
+					t.Errorf("%s serves %q", selector, doc.names)
+				}
+			}
+		})
+	}
+}
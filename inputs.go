@@ -0,0 +1,434 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// everything named on the command line, read one after another like cat
+// would. no names at all means stdin, and so does "-".
+//
+// with headers, every input starts with a header saying what it is, like tail
+// does. headers are read like everything else unless there's somewhere to
+// write them instantly.
+type inputs struct {
+	names   []string
+	cur     io.ReadCloser
+	opened  int
+	pending []byte
+
+	headers bool
+	instant func(header []byte) error
+	follow  bool
+	bom     string
+}
+
+func newInputs(names []string) *inputs {
+	if len(names) == 0 {
+		names = []string{"-"}
+	}
+	return &inputs{names: names}
+}
+
+func header(name string, first bool) []byte {
+	if name == "-" {
+		name = "standard input"
+	}
+	if first {
+		return []byte(fmt.Sprintf("==> %s <==\n", name))
+	}
+	return []byte(fmt.Sprintf("\n==> %s <==\n", name))
+}
+
+func (in *inputs) Read(p []byte) (int, error) {
+	for {
+		if len(in.pending) > 0 {
+			n := copy(p, in.pending)
+			in.pending = in.pending[n:]
+			return n, nil
+		}
+
+		if in.cur == nil {
+			if len(in.names) == 0 {
+				return 0, io.EOF
+			}
+			f, err := openInput(in.names[0])
+			if err != nil {
+				return 0, err
+			}
+			if in.follow && len(in.names) == 1 {
+				f = following(f)
+			}
+			if in.bom != "ignore" {
+				f = byteOrderMarked(f, in.bom == "keep")
+			}
+
+			if in.headers {
+				h := header(in.names[0], in.opened == 0)
+				if in.instant == nil {
+					in.pending = h
+				} else if err := in.instant(h); err != nil {
+					f.Close()
+					return 0, err
+				}
+			}
+			in.cur, in.names = f, in.names[1:]
+			in.opened++
+			continue
+		}
+
+		n, err := in.cur.Read(p)
+		if err == io.EOF {
+			in.cur.Close()
+			in.cur = nil
+			err = nil
+		}
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+}
+
+// stop reading partway through
+func (in *inputs) Close() error {
+	in.names = nil
+	if in.cur == nil {
+		return nil
+	}
+	err := in.cur.Close()
+	in.cur = nil
+	return err
+}
+
+// open one input, with errors that say which one. URLs are fetched, and read
+// as the body comes in.
+func openInput(name string) (io.ReadCloser, error) {
+	if name == "-" {
+		return ioutil.NopCloser(os.Stdin), nil
+	}
+	if strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://") {
+		return fetch(name)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		if pathErr, isPathErr := err.(*os.PathError); isPathErr {
+			return nil, fmt.Errorf("error opening %s: %s", name, pathErr.Err)
+		}
+		return nil, fmt.Errorf("error opening %s: %s", name, err)
+	}
+	return f, nil
+}
+
+func fetch(url string) (io.ReadCloser, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %s: %s", url, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("error fetching %s: %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// read UTF-8 no matter what byte order mark the input starts with. UTF-16 is
+// converted to UTF-8, and a UTF-8 BOM is stripped out unless it's kept. a kept
+// BOM always comes out as UTF-8, even if it started out as UTF-16.
+func byteOrderMarked(rc io.ReadCloser, keep bool) io.ReadCloser {
+	br := bufio.NewReader(rc)
+
+	// only wait for more than whatever comes first if it might be the start of
+	// a mark. a slow stream shouldn't sit in the buffer behind three bytes.
+	br.Peek(1)
+	mark, _ := br.Peek(br.Buffered())
+	switch {
+	case len(mark) == 0:
+	case len(mark) < 3 && bytes.HasPrefix([]byte{0xef, 0xbb, 0xbf}, mark):
+		mark, _ = br.Peek(3)
+	case len(mark) < 2 && (mark[0] == 0xff || mark[0] == 0xfe):
+		mark, _ = br.Peek(2)
+	}
+
+	var r io.Reader = br
+	switch {
+	case bytes.HasPrefix(mark, []byte{0xef, 0xbb, 0xbf}):
+		if !keep {
+			br.Discard(3)
+		}
+	case bytes.HasPrefix(mark, []byte{0xff, 0xfe}), bytes.HasPrefix(mark, []byte{0xfe, 0xff}):
+		// the decoder figures out the byte order, and throws the mark away
+		r = transform.NewReader(br, unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder())
+		if keep {
+			r = io.MultiReader(strings.NewReader("\ufeff"), r)
+		}
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{r, rc}
+}
+
+// look up a character set by any of the names people call it
+func encodingFor(name string) (encoding.Encoding, error) {
+	names := []string{
+		name,
+		strings.Replace(name, "-", "_", -1),
+		strings.Replace(name, "-", "", -1),
+	}
+	for _, name := range names {
+		if enc, err := htmlindex.Get(name); err == nil {
+			return enc, nil
+		}
+		if enc, err := ianaindex.IANA.Encoding(name); err == nil && enc != nil {
+			return enc, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown encoding: %s", name)
+}
+
+// connect to a TCP address like host:port, or a unix socket if it looks like
+// a path
+func dial(addr string) (net.Conn, error) {
+	network := "tcp"
+	if strings.HasPrefix(addr, "unix:") {
+		network, addr = "unix", strings.TrimPrefix(addr, "unix:")
+	} else if strings.ContainsRune(addr, '/') {
+		network = "unix"
+	}
+
+	return net.Dial(network, addr)
+}
+
+// only part of everything there is to read. skip bytes and then lines, and
+// then read at most limit bytes and limit lines. a negative limit never ends.
+type slice struct {
+	r                    io.Reader
+	skipBytes, skipLines int64
+	maxBytes, maxLines   int64
+}
+
+func (s *slice) Read(p []byte) (int, error) {
+	for {
+		if s.maxBytes == 0 || s.maxLines == 0 {
+			return 0, io.EOF
+		}
+
+		n, err := s.r.Read(p)
+		data := p[:n]
+
+		if s.skipBytes > 0 {
+			skip := s.skipBytes
+			if skip > int64(len(data)) {
+				skip = int64(len(data))
+			}
+			data, s.skipBytes = data[skip:], s.skipBytes-skip
+		}
+		for s.skipBytes == 0 && s.skipLines > 0 && len(data) > 0 {
+			i := bytes.IndexByte(data, '\n')
+			if i < 0 {
+				data = data[:0]
+				break
+			}
+			data, s.skipLines = data[i+1:], s.skipLines-1
+		}
+
+		if s.maxBytes > 0 && int64(len(data)) > s.maxBytes {
+			data = data[:s.maxBytes]
+		}
+		if s.maxLines > 0 {
+			for i := 0; i < len(data); i++ {
+				if data[i] != '\n' {
+					continue
+				}
+				if s.maxLines--; s.maxLines == 0 {
+					data = data[:i+1]
+				}
+			}
+		}
+		if s.maxBytes > 0 {
+			s.maxBytes -= int64(len(data))
+		}
+
+		n = copy(p, data)
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+}
+
+// how often to check a followed file for more
+const followInterval = 250 * time.Millisecond
+
+// keep reading a file as it grows, like tail -f. anything that isn't a regular
+// file can't grow, so it's read like usual.
+func following(rc io.ReadCloser) io.ReadCloser {
+	f, ok := rc.(*os.File)
+	if !ok {
+		return rc
+	}
+	if stat, err := f.Stat(); err != nil || !stat.Mode().IsRegular() {
+		return rc
+	}
+	return &follower{f}
+}
+
+type follower struct {
+	*os.File
+}
+
+func (f *follower) Read(p []byte) (int, error) {
+	for {
+		n, err := f.File.Read(p)
+		if err != io.EOF {
+			return n, err
+		}
+		if n > 0 {
+			return n, nil
+		}
+
+		// start over if the file was truncated out from under us
+		offset, err := f.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, err
+		}
+		if stat, err := f.Stat(); err == nil && stat.Size() < offset {
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		time.Sleep(followInterval)
+	}
+}
+
+// give up on input that stops coming. with a marker, write the marker every
+// time nothing arrives in time instead, and keep waiting.
+type idle struct {
+	r       io.Reader
+	timeout time.Duration
+	marker  []byte
+
+	chunks chan chunk
+	left   []byte
+	err    error
+}
+
+type chunk struct {
+	data []byte
+	err  error
+}
+
+func (i *idle) Read(p []byte) (int, error) {
+	if len(i.left) > 0 {
+		n := copy(p, i.left)
+		i.left = i.left[n:]
+		return n, nil
+	}
+	if i.err != nil {
+		return 0, i.err
+	}
+
+	// reads can't be interrupted, so they happen somewhere they can be
+	// abandoned
+	if i.chunks == nil {
+		i.chunks = make(chan chunk)
+		go i.read()
+	}
+
+	timer := time.NewTimer(i.timeout)
+	defer timer.Stop()
+
+	select {
+	case c := <-i.chunks:
+		n := copy(p, c.data)
+		i.left, i.err = c.data[n:], c.err
+		if n == 0 {
+			return 0, i.err
+		}
+		return n, nil
+	case <-timer.C:
+		if len(i.marker) == 0 {
+			return 0, fmt.Errorf("no input for %s", i.timeout)
+		}
+		return copy(p, i.marker), nil
+	}
+}
+
+func (i *idle) read() {
+	for {
+		buf := make([]byte, 32*1024)
+		n, err := i.r.Read(buf)
+		i.chunks <- chunk{buf[:n], err}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// read everything as soon as it comes in, copying it to tee right away, and
+// hold onto it until it's read again more patiently
+type ahead struct {
+	mu   sync.Mutex
+	more *sync.Cond
+	buf  []byte
+	err  error
+}
+
+func readAhead(src io.Reader, tee io.Writer) *ahead {
+	a := &ahead{}
+	a.more = sync.NewCond(&a.mu)
+
+	go func() {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := src.Read(buf)
+			if n > 0 {
+				if _, werr := tee.Write(buf[:n]); werr != nil && err == nil {
+					err = werr
+				}
+			}
+
+			a.mu.Lock()
+			a.buf = append(a.buf, buf[:n]...)
+			a.err = err
+			a.more.Broadcast()
+			a.mu.Unlock()
+
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return a
+}
+
+func (a *ahead) Read(p []byte) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for len(a.buf) == 0 && a.err == nil {
+		a.more.Wait()
+	}
+	if len(a.buf) == 0 {
+		return 0, a.err
+	}
+	n := copy(p, a.buf)
+	a.buf = a.buf[n:]
+	return n, nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestSlice(t *testing.T) {
+	input := "one\ntwo\nthree\n"
+	tests := []struct {
+		name                 string
+		skipBytes, skipLines int64
+		maxBytes, maxLines   int64
+		want                 string
+	}{
+		{"everything", 0, 0, -1, -1, input},
+		{"skip bytes", 2, 0, -1, -1, "e\ntwo\nthree\n"},
+		{"skip lines", 0, 2, -1, -1, "three\n"},
+		// bytes are skipped first, and then lines
+		{"skip bytes then lines", 2, 1, -1, -1, "two\nthree\n"},
+		{"skip past the end", 0, 5, -1, -1, ""},
+		{"max bytes", 0, 0, 5, -1, "one\nt"},
+		{"max lines", 0, 0, -1, 2, "one\ntwo\n"},
+		{"whichever comes first", 0, 0, 5, 1, "one\n"},
+		{"skip and max", 4, 0, -1, 1, "two\n"},
+		{"nothing at all", 0, 0, 0, -1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// reading a byte at a time skips and stops in the same places
+			readers := map[string]io.Reader{
+				"all at once":      strings.NewReader(input),
+				"a byte at a time": iotest.OneByteReader(strings.NewReader(input)),
+			}
+			for how, r := range readers {
+				s := &slice{r: r, skipBytes: tt.skipBytes, skipLines: tt.skipLines, maxBytes: tt.maxBytes, maxLines: tt.maxLines}
+				got, err := io.ReadAll(s)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(got) != tt.want {
+					t.Errorf("reading %s got %q, want %q", how, got, tt.want)
+				}
+			}
+		})
+	}
+}
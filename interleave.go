@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+var (
+	streams      = setFlag{}
+	streamSpeeds = setFlag{}
+)
+
+func init() {
+	flag.Var(streams, "stream", "play lines from a file or a fifo alongside every other -stream, like name=path. every line starts with its name. can be given more than once")
+	flag.Var(streamSpeeds, "stream-speed", "how much faster one -stream goes than everything else, like name=2x")
+}
+
+// play every stream at once, a whole line at a time. every stream waits at its
+// own pace, but only one line is written at a time, so they never end up
+// tangled together. lines are played in the order they're read, and streams
+// keep reading while someone else's line is being played. interleave returns
+// once every stream has ended.
+func interleave(dst io.Writer, opts []slow.Option) error {
+	if needsLength() {
+		return errors.New("can't ramp over streams without a -ramp-window, since there's no telling how long they are")
+	}
+	if flag.NArg() > 0 {
+		return errors.New("can't play files alongside -stream. give every file a -stream of its own")
+	}
+
+	var names []string
+	width := 0
+	for name := range streams {
+		names = append(names, name)
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+	sort.Strings(names)
+
+	for name := range streamSpeeds {
+		if _, ok := streams[name]; !ok {
+			return fmt.Errorf("there's no stream named %s to speed up", name)
+		}
+	}
+
+	var (
+		wg    sync.WaitGroup
+		lines = make(chan queuedLine, queueLength)
+		done  = make(chan struct{})
+		errs  = make(chan error, len(names))
+	)
+	defer close(done)
+	for _, name := range names {
+		speed := 1.0
+		if s, ok := streamSpeeds[name]; ok {
+			m, err := strconv.ParseFloat(strings.TrimSuffix(s, "x"), 64)
+			if err != nil || m <= 0 {
+				return fmt.Errorf("invalid speed for %s: %s", name, s)
+			}
+			speed = m
+		}
+
+		// patience can remember things, so every stream gets its own
+		_, own, err := optionsFromFlags(0)
+		if err != nil {
+			return err
+		}
+		w := slow.New(dst, append(opts, own...)...)
+		w.SetMultiplier(speed)
+
+		prefix := fmt.Sprintf("[%-*s] ", width, name)
+		path := streams[name]
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := stream(path, prefix, w, lines, done); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+
+	for line := range lines {
+		if err := line.play(dst); err != nil {
+			return err
+		}
+	}
+	close(errs)
+	return <-errs
+}
+
+// how many lines can wait their turn before streams stop reading
+const queueLength = 64
+
+// a line from a stream, waiting to be played by that stream's Writer
+type queuedLine struct {
+	prefix string
+	line   []byte
+	w      *slow.Writer
+}
+
+func (l queuedLine) play(dst io.Writer) error {
+	if _, err := io.WriteString(dst, l.prefix); err != nil {
+		return err
+	}
+	if _, err := l.w.Write(l.line); err != nil {
+		return err
+	}
+	return l.w.Close()
+}
+
+// read one stream a line at a time, and queue every line up to be played.
+// once done is closed, nobody's playing lines anymore.
+func stream(path, prefix string, w *slow.Writer, lines chan<- queuedLine, done <-chan struct{}) error {
+	f, err := openInput(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if *follow {
+		f = following(f)
+	}
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			if line[len(line)-1] != '\n' {
+				line = append(line, '\n')
+			}
+			select {
+			case lines <- queuedLine{prefix, line, w}:
+			case <-done:
+				return nil
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+	"github.com/blinsay/aslap/slow/slowtest"
+)
+
+func TestInterleave(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a":  "a1\na2\n",
+		"bb": "b1\nb2\nb3",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		streams[name] = path
+	}
+	defer func() {
+		for name := range files {
+			delete(streams, name)
+		}
+	}()
+
+	var out bytes.Buffer
+	if err := interleave(&out, []slow.Option{slow.WithClock(slowtest.NewClock(time.Unix(0, 0)))}); err != nil {
+		t.Fatal(err)
+	}
+
+	// every line comes out whole, with its name, and every stream's lines
+	// stay in order
+	var a, bb []string
+	for _, line := range strings.SplitAfter(out.String(), "\n") {
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "[a ] "):
+			a = append(a, strings.TrimPrefix(line, "[a ] "))
+		case strings.HasPrefix(line, "[bb] "):
+			bb = append(bb, strings.TrimPrefix(line, "[bb] "))
+		default:
+			t.Errorf("tangled line %q", line)
+		}
+	}
+	if got := strings.Join(a, ""); got != files["a"] {
+		t.Errorf("a played %q, want %q", got, files["a"])
+	}
+	// a stream that doesn't end with a newline gets one anyway
+	if got := strings.Join(bb, ""); got != files["bb"]+"\n" {
+		t.Errorf("bb played %q, want %q", got, files["bb"]+"\n")
+	}
+}
+
+func TestInterleaveFiles(t *testing.T) {
+	streams["a"] = os.DevNull
+	defer delete(streams, "a")
+	flag.CommandLine.Parse([]string{"file.txt"})
+	defer flag.CommandLine.Parse(nil)
+
+	if err := interleave(&bytes.Buffer{}, nil); err == nil {
+		t.Error("played a file alongside a stream")
+	}
+}
@@ -1,139 +1,554 @@
 package main
 
 import (
-	"bufio"
+	"bytes"
+	"context"
 	"flag"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"strings"
 	"time"
 	"unicode/utf8"
+
+	"github.com/blinsay/aslap/slow"
+	"golang.org/x/text/transform"
 )
 
 var (
-	initial = flag.Duration("base", 1*time.Second, "the base delay per character")
-	step    = flag.Duration("step", 100*time.Millisecond, "the amount of proportial delay added per rune")
-	bits    = flag.Uint("bits", 3, "the number of bits per rune used to determine an appropriate delay")
-	debug   = flag.Bool("debug", false, "print the input character and the calculated delay instead of the output unmodified")
+	initial        = flag.Duration("base", 1*time.Second, "the base delay per character")
+	step           = flag.Duration("step", 100*time.Millisecond, "the amount of proportial delay added per rune")
+	bits           = flag.Uint("bits", 3, "the number of bits per rune used to determine an appropriate delay")
+	hash           = flag.String("hash", "none", "hash every rune before using its bits to determine a delay: none, fnv, or crc32")
+	debug          = flag.Bool("debug", false, "print the input character and the calculated delay instead of the output unmodified")
+	unit           = flag.String("unit", "rune", "the unit of output to be patient about: rune, word, line, grapheme, or byte")
+	scale          = flag.Bool("scale-by-length", false, "wait for every rune in a word or line, instead of once per word or line, so longer lines take longer")
+	preset         = flag.String("preset", "", "a named set of defaults to be patient with: "+strings.Join(slow.Presets(), ", "))
+	morseOutput    = flag.String("morse-output", "text", "with -morse, write text, the morse code itself, or both")
+	typos          = flag.Float64("typos", 0, "the chance of making a typo on any letter, from 0 to 1, and then fixing it")
+	typoNotice     = flag.Duration("typo-notice", 500*time.Millisecond, "how long it takes to notice a typo before fixing it")
+	typoErase      = flag.String("typo-erase", "backspace", "how to erase typos: backspace, or csi for terminals that need escape sequences")
+	wave           = flag.Duration("wave", 0, "smoothly speed up and slow down, over and over, taking this long for every wave")
+	waveAmplitude  = flag.Float64("wave-amplitude", 2, "with -wave, how many times as fast to go at the crest of a wave, and as slow in the trough")
+	replay         = flag.String("replay", "", "replay a recording from the input with its original timing: script or ttyrec")
+	replayTiming   = flag.String("replay-timing", "", "with -replay script, the timing file script -t wrote")
+	replaySpeed    = flag.Float64("replay-speed", 1, "with -replay, replay this many times as fast as the original")
+	headers        = flag.Bool("headers", false, "start every input with a header like \"==> name <==\", the way tail does")
+	instantHeaders = flag.Bool("instant-headers", false, "write headers between inputs immediately instead of patiently")
+	follow         = flag.Bool("f", false, "keep reading the last input as it grows, like tail -f")
+	instantEscapes = flag.Bool("instant-escapes", true, "write ANSI escape sequences like colors all at once, without waiting for them")
+	fromEncoding   = flag.String("from-encoding", "", "convert input from this character set to UTF-8 first, like latin-1, windows-1252, or shift-jis")
+	bom            = flag.String("bom", "strip", "what to do with a byte order mark at the start of an input: strip, keep, or ignore to treat it like anything else. UTF-16 is converted to UTF-8 unless it's ignored. with -from-encoding, the encoding handles it instead")
+	liveEcho       = flag.Bool("echo", false, "echo back whatever's typed at the terminal, patiently. ^C or ^D to stop")
+	watchMode      = flag.Bool("watch", false, "clear the screen and play the file again every time it changes")
+	connect        = flag.String("connect", "", "connect to host:port, or to a unix socket at a path, and read whatever comes in instead of files")
+	newlines       = flag.String("newlines", "keep", "what to do with line endings: keep them as they are, turn \\r\\n into \\n with lf, or write every \\n as \\r\\n with crlf")
+	inputTimeout   = flag.Duration("input-timeout", 0, "give up if no input arrives for this long")
+	timeoutMarker  = flag.String("timeout-marker", "", "instead of giving up after -input-timeout, write this and keep waiting")
+	skipBytes      = flag.Int64("skip-bytes", 0, "skip this many bytes of input before writing anything")
+	skipLines      = flag.Int64("skip-lines", 0, "skip this many lines of input, after skipping bytes, before writing anything")
+	maxBytes       = flag.Int64("max-bytes", 0, "stop after writing this many bytes. zero means no limit")
+	maxLines       = flag.Int64("max-lines", 0, "stop after writing this many lines. zero means no limit")
+	shuffle        = flag.Bool("shuffle", false, "read everything first, and then play its lines in a random order. use -seed to get the same order again")
+	reverse        = flag.String("reverse", "", "read everything first, and then play it backwards by \"line\", last line first, or by \"rune\", all mirrored")
+	schedule       = flag.String("schedule", "", "a file with a timeline of speed changes, one per line, like \"10s 0.25x\"")
+	total          = flag.Duration("total", 0, "read everything first, and then spread it out so that it takes exactly this long")
+	outputPath     = flag.String("output", "", "write to this file, fifo, or device instead of stdout")
+	teePath        = flag.String("tee", "", "also write everything to this file right away, without waiting, while the output stays patient")
+	stats          = flag.Bool("stats", false, "print a summary of everything written to stderr when done")
 )
 
+// where everything ends up
+var out = os.Stdout
+
 func init() {
+	flag.StringVar(outputPath, "o", "", "shorthand for -output")
 	flag.Usage = func() {
 		fmt.Fprintf(flag.CommandLine.Output(), "as slow as possible\n\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] [file or url ...]\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "       %s [flags] -- command [args ...]\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "       %s record [flags] timing-file\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "       %s serve %s [flags] [file or url ...]\n", os.Args[0], strings.Join(serverNames(), "|"))
+		fmt.Fprintf(flag.CommandLine.Output(), "       %s render %s [flags] [file or url ...]\n\n", os.Args[0], strings.Join(rendererNames(), "|"))
 		flag.PrintDefaults()
 	}
 }
 
+// everything aslap can do besides be slow
+var commands = map[string]func(args []string) int{
+	"record": record,
+	"render": render,
+	"serve":  serve,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			os.Exit(command(os.Args[2:]))
+		}
+	}
+
 	flag.Parse()
 
-	src, err := terminalInputs(os.Stdin, flag.Args())
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
+	in := newInputs(flag.Args())
+	in.follow = *follow
+	in.bom = *bom
+	if *fromEncoding != "" {
+		// converting from anything else already says what the input is, so
+		// a byte order mark is left for its decoder. looking for one first
+		// would decode UTF-16 twice.
+		in.bom = "ignore"
+	}
+	in.headers = *headers
+	var src io.Reader = in
+	switch *bom {
+	case "strip", "keep", "ignore":
+	default:
+		fmt.Fprintf(os.Stderr, "unknown way to handle byte order marks: %s\n", *bom)
 		os.Exit(1)
 	}
-	dst := io.Writer(os.Stdout)
 
-	delay := bePatient(*bits, *initial, *step)
+	var cmd *child
+	if runningCommand() {
+		c, err := startCommand(flag.Args())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exit(1)
+		}
+		cmd, src = c, c
+		// giving up early means the command doesn't get to finish either
+		atExit(func() {
+			c.Kill()
+			c.Close()
+		})
+	}
+
+	if *serialPort != "" {
+		port, err := openSerial()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exit(1)
+		}
+		src = port
+	}
+
+	if *connect != "" {
+		conn, err := dial(*connect)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exit(1)
+		}
+		src = conn
+	}
+
+	var typed *keystrokes
+	if *liveEcho {
+		k, err := startEcho()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exit(1)
+		}
+		typed, src = k, k
+		atExit(func() { k.Close() })
+	}
+	if *inputTimeout > 0 {
+		src = &idle{r: src, timeout: *inputTimeout, marker: []byte(*timeoutMarker)}
+	}
+	if *fromEncoding != "" {
+		enc, err := encodingFor(*fromEncoding)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exit(1)
+		}
+		src = transform.NewReader(src, enc.NewDecoder())
+	}
+
+	switch *newlines {
+	case "keep":
+	case "lf", "crlf":
+		src = transform.NewReader(src, lfOnly{})
+	default:
+		fmt.Fprintf(os.Stderr, "unknown newlines: %s. try keep, lf, or crlf\n", *newlines)
+		exit(1)
+	}
+
+	if *templating {
+		rendered, err := expand(src)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exit(1)
+		}
+		src = rendered
+	}
+
+	if *skipBytes < 0 || *skipLines < 0 || *maxBytes < 0 || *maxLines < 0 {
+		fmt.Fprintln(os.Stderr, "can't skip or read a negative amount of input")
+		exit(1)
+	}
+	if *skipBytes > 0 || *skipLines > 0 || *maxBytes > 0 || *maxLines > 0 {
+		src = &slice{r: src, skipBytes: *skipBytes, skipLines: *skipLines, maxBytes: orForever(*maxBytes), maxLines: orForever(*maxLines)}
+	}
+
+	if *shuffle {
+		data, err := ioutil.ReadAll(src)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exit(1)
+		}
+		src = bytes.NewReader(shuffleLines(data, newRand()))
+	}
+
+	if *reverse != "" {
+		backwards, ok := map[string]func([]byte) []byte{
+			"line": reverseLines,
+			"rune": reverseRunes,
+		}[*reverse]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "can't reverse by %s. try line or rune\n", *reverse)
+			exit(1)
+		}
+		data, err := ioutil.ReadAll(src)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exit(1)
+		}
+		src = bytes.NewReader(backwards(data))
+	}
+
+	regular := false
+	if *outputPath != "" {
+		f, isFile, err := openOutput(*outputPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exit(1)
+		}
+		out, regular = f, isFile
+	}
+
+	dst := io.Writer(out)
+	if *newlines == "crlf" {
+		dst = transform.NewWriter(dst, crlf{})
+	}
+	switch *morseOutput {
+	case "text":
+	case "morse":
+		dst = slow.NewMorseWriter(dst, false)
+	case "both":
+		dst = slow.NewMorseWriter(dst, true)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown morse output: %s\n", *morseOutput)
+		exit(1)
+	}
+
+	length := 0
+	if needsLength() && len(streams) == 0 {
+		data, err := ioutil.ReadAll(src)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exit(1)
+		}
+		src, length = bytes.NewReader(data), utf8.RuneCount(data)
+	}
+
+	var tee *os.File
+	if *teePath != "" {
+		if *replay != "" {
+			fmt.Fprintln(os.Stderr, "can't tee a replay, since it's already been written once")
+			exit(1)
+		}
+		f, _, err := openOutput(*teePath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exit(1)
+		}
+		tee, src = f, readAhead(src, f)
+	}
+
+	patience, opts, err := optionsFromFlags(length)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		exit(1)
+	}
+	if regular {
+		// anyone reading a file sees everything as soon as it's written.
+		// syncing it all the way to disk after every rune only makes the
+		// disk slow too, so it happens once at the end.
+		opts = append(opts, slow.WithFlush(func() error { return nil }))
+	}
 	if *debug {
 		dst = ioutil.Discard
-		delay = printImpatiently(os.Stdout, delay)
+		opts = append(opts, slow.WithOnRune(printImpatiently(os.Stdout)))
 	}
 
-	copyRunesWithPatience(dst, src, delay)
-}
+	if *watchMode {
+		if flag.NArg() != 1 {
+			fmt.Fprintln(os.Stderr, "watching needs exactly one file to watch")
+			exit(1)
+		}
+		err := watch(flag.Arg(0), dst, patience, opts)
+		fmt.Fprintln(os.Stderr, err)
+		exit(1)
+	}
 
-func terminalInputs(src *os.File, fallbacks []string) (io.Reader, error) {
-	if stat, _ := src.Stat(); stat.Mode()&os.ModeCharDevice == 0 {
-		return os.Stdin, nil
+	if len(streams) > 0 {
+		if err := interleave(dst, opts); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exit(1)
+		}
+		exit(0)
 	}
 
-	var rdrs []io.Reader
-	for _, filename := range fallbacks {
-		f, err := os.Open(filename)
-		if err != nil {
-			if pathErr, isPathErr := err.(*os.PathError); isPathErr {
-				return nil, fmt.Errorf("error opening %s: %s", filename, pathErr.Err)
+	w := slow.New(dst, opts...)
+	if *instantHeaders && in.headers {
+		if name := readingAhead(); name != "" {
+			fmt.Fprintf(os.Stderr, "can't write headers instantly with -%s, since inputs are read before it's time for their headers\n", name)
+			exit(1)
+		}
+		in.instant = func(header []byte) error {
+			// anything held from the last input has to come out first
+			if err := w.Close(); err != nil {
+				return err
 			}
-			return nil, fmt.Errorf("error opening %s: %s", filename, err)
+			_, err := dst.Write(header)
+			return err
+		}
+	}
+	if *wave > 0 {
+		if *waveAmplitude < 1 {
+			fmt.Fprintf(os.Stderr, "invalid wave amplitude: %v\n", *waveAmplitude)
+			exit(1)
+		}
+		go slow.Wave{Period: *wave, Amplitude: *waveAmplitude}.Play(context.Background(), w, nil)
+	}
+	if *schedule != "" {
+		s, err := readSchedule(*schedule)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exit(1)
+		}
+		go s.Play(context.Background(), w, nil)
+	}
+
+	if *replay != "" {
+		err = replayRecording(w, src)
+	} else if *total > 0 {
+		err = copyStretched(w, src, *total)
+	} else {
+		_, err = io.Copy(w, src)
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if typed != nil {
+		typed.Close()
+	}
+	if tee != nil {
+		if cerr := tee.Close(); err == nil {
+			err = cerr
 		}
-		rdrs = append(rdrs, f)
 	}
-	return io.MultiReader(rdrs...), nil
+	if out != os.Stdout {
+		if regular {
+			out.Sync()
+		}
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+	}
+
+	// everything the command wrote has been written by now, unless something
+	// went wrong
+	status := 0
+	if cmd != nil {
+		if err != nil {
+			cmd.Kill()
+		}
+		status = exitCode(cmd.Close())
+	}
+
+	summary := w.Stats()
+	if *stats {
+		printStats(os.Stderr, summary)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(status)
 }
 
-// a func that determines how long to wait
-type patience func(rune) time.Duration
+// everything that has to be undone before exiting early, like putting the
+// terminal back the way it was
+var cleanups []func()
 
-// help debug patience by showing exactly how patient we're being
-func printImpatiently(dst io.Writer, f patience) patience {
-	return func(b rune) time.Duration {
-		delay := f(b)
-		fmt.Fprintf(dst, "%q %U %s\n", string(b), b, delay)
-		return delay
+// run f before exiting early
+func atExit(f func()) {
+	cleanups = append(cleanups, f)
+}
+
+// exit with status, after undoing everything that's been set up so far, last
+// thing first. os.Exit doesn't run deferred functions, so nothing else would.
+func exit(status int) {
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		cleanups[i]()
+	}
+	os.Exit(status)
+}
+
+func readSchedule(filename string) (slow.Schedule, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
 	}
+	defer f.Close()
+
+	s, err := slow.ParseSchedule(f)
+	if err != nil {
+		return nil, fmt.Errorf("error reading schedule %s: %s", filename, err)
+	}
+	return s, nil
 }
 
-// be patient
-func bePatient(bits uint, initial, step time.Duration) patience {
-	if bits >= 8 {
-		panic("too many bits")
+// read everything, and write it back out so it takes exactly d
+func copyStretched(w *slow.Writer, src io.Reader, d time.Duration) error {
+	data, err := ioutil.ReadAll(src)
+	if err != nil {
+		return err
 	}
-	mask := rune((0x1 << bits) - 1)
 
-	return func(b rune) time.Duration {
-		return initial + step*time.Duration(mask&b)
+	plan, err := w.Plan(data, 0)
+	if err != nil {
+		return err
 	}
+	_, err = w.WriteBatch(plan.Stretch(d))
+	return err
 }
 
-// copy runes from src to dst, being patient about writing every byte.
-func copyRunesWithPatience(dst io.Writer, src io.Reader, patience patience) error {
-	flush := makeFlush(dst)
+// read a recording and replay it exactly as it was recorded
+func replayRecording(w *slow.Writer, src io.Reader) error {
+	if *replaySpeed <= 0 {
+		return fmt.Errorf("invalid replay speed: %v", *replaySpeed)
+	}
 
-	scanner := bufio.NewScanner(src)
-	scanner.Split(bufio.ScanRunes)
+	var plan slow.Plan
+	var err error
+	switch *replay {
+	case "script":
+		if *replayTiming == "" {
+			return fmt.Errorf("replaying script needs a -replay-timing file")
+		}
+		f, ferr := os.Open(*replayTiming)
+		if ferr != nil {
+			return ferr
+		}
+		defer f.Close()
+		plan, err = slow.ParseScriptTiming(f, src)
+	case "ttyrec":
+		plan, err = slow.ParseTtyrec(src)
+	default:
+		return fmt.Errorf("unknown recording format: %s", *replay)
+	}
+	if err != nil {
+		return fmt.Errorf("error reading recording: %s", err)
+	}
 
-	for scanner.Scan() {
-		bs := scanner.Bytes()
-		for len(bs) > 0 {
-			r, size := utf8.DecodeRune(bs)
-			delay := patience(r)
+	_, err = w.WriteBatch(plan.Stretch(time.Duration(float64(plan.Total) / *replaySpeed)))
+	return err
+}
 
-			if _, err := dst.Write(bs[:size]); err != nil {
-				return err
-			}
+// the first flag that has inputs read ahead of being written, either all at
+// once before writing anything or on another goroutine while writing, if
+// there is one
+func readingAhead() string {
+	switch {
+	case needsLength():
+		return "ramp"
+	case *total > 0:
+		return "total"
+	case *replay != "":
+		return "replay"
+	case *teePath != "":
+		return "tee"
+	case *inputTimeout > 0:
+		return "input-timeout"
+	case *templating:
+		return "template"
+	case *shuffle:
+		return "shuffle"
+	case *reverse != "":
+		return "reverse"
+	}
+	return ""
+}
+
+// zero means no limit at all
+func orForever(n int64) int64 {
+	if n == 0 {
+		return -1
+	}
+	return n
+}
 
-			flush()
-			time.Sleep(delay)
+// open somewhere to write. files start over empty, and anything that isn't a
+// file, like a fifo or a serial port, is written to as it is. regular is true
+// for files.
+func openOutput(name string) (f *os.File, regular bool, err error) {
+	if stat, err := os.Stat(name); err == nil && !stat.Mode().IsRegular() {
+		f, err := os.OpenFile(name, os.O_WRONLY, 0)
+		return f, false, err
+	}
+	f, err = os.Create(name)
+	return f, true, err
+}
+
+// returns true if everything after the flags is a command to run, because the
+// flags ended with --
+func runningCommand() bool {
+	end := len(os.Args) - flag.NArg() - 1
+	return flag.NArg() > 0 && end > 0 && os.Args[end] == "--"
+}
 
-			bs = bs[size:]
+// returns true if input shows up whenever it shows up, instead of being there
+// to read all along
+func liveInput() bool {
+	if *follow || *liveEcho || *connect != "" || *serialPort != "" || *inputTimeout > 0 || runningCommand() {
+		return true
+	}
+	if flag.NArg() == 0 {
+		return !isRegular(os.Stdin)
+	}
+	for _, name := range flag.Args() {
+		if name == "-" && !isRegular(os.Stdin) {
+			return true
 		}
 	}
-	return scanner.Err()
+	return false
 }
 
-// returns a func that flushes this writer. if the writer is unflushable,
-// returns a noop.
-//
-// this is here just in case you'd like to aslap an io.Writer that isn't
-// an os.File
-func makeFlush(w io.Writer) func() {
-	// http.Flusher
-	type flusher interface{ Flush() }
-	// bufio.Writer
-	type safeFlusher interface{ Flush() error }
-	// os.File
-	type syncer interface{ Sync() error }
+// returns true if a flag was set on the command line
+func isSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
 
-	switch t := w.(type) {
-	case flusher:
-		return t.Flush
-	case safeFlusher:
-		return func() { t.Flush() }
-	case syncer:
-		return func() { t.Sync() }
-	default:
-		return func() {}
+// help debug patience by showing exactly how patient we're being
+func printImpatiently(dst io.Writer) func(rune, time.Duration, int) {
+	return func(b rune, delay time.Duration, _ int) {
+		fmt.Fprintf(dst, "%q %U %s\n", string(b), b, delay)
 	}
 }
+
+// show off how patient we were
+func printStats(dst io.Writer, s slow.Stats) {
+	fmt.Fprintf(dst, "runes:  %d\n", s.Runes)
+	fmt.Fprintf(dst, "bytes:  %d\n", s.Bytes)
+	fmt.Fprintf(dst, "slept:  %s\n", s.Slept)
+	fmt.Fprintf(dst, "wall:   %s\n", s.Wall)
+	fmt.Fprintf(dst, "delays: %s - %s\n", s.MinDelay, s.MaxDelay)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"golang.org/x/text/transform"
+)
+
+// turns every \r\n into plain \n. a \r on its own is left alone, since it's
+// probably redrawing a progress bar or something like it.
+type lfOnly struct{ transform.NopResetter }
+
+func (lfOnly) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for nSrc < len(src) {
+		if src[nSrc] == '\r' {
+			if nSrc+1 == len(src) && !atEOF {
+				return nDst, nSrc, transform.ErrShortSrc
+			}
+			if nSrc+1 < len(src) && src[nSrc+1] == '\n' {
+				nSrc++
+				continue
+			}
+		}
+		if nDst == len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		dst[nDst] = src[nSrc]
+		nDst++
+		nSrc++
+	}
+	return nDst, nSrc, nil
+}
+
+// turns every \n into \r\n, for anything that doesn't go back to the start of
+// the line on its own
+type crlf struct{ transform.NopResetter }
+
+func (crlf) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for ; nSrc < len(src); nSrc++ {
+		if src[nSrc] == '\n' {
+			if nDst+2 > len(dst) {
+				return nDst, nSrc, transform.ErrShortDst
+			}
+			dst[nDst] = '\r'
+			nDst++
+		} else if nDst == len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		dst[nDst] = src[nSrc]
+		nDst++
+	}
+	return nDst, nSrc, nil
+}
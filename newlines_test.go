@@ -0,0 +1,51 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"golang.org/x/text/transform"
+)
+
+func TestNewlines(t *testing.T) {
+	tests := []struct {
+		name  string
+		t     transform.Transformer
+		input string
+		want  string
+	}{
+		{"lf nothing", lfOnly{}, "", ""},
+		{"lf", lfOnly{}, "a\r\nb\r\n", "a\nb\n"},
+		{"lf already", lfOnly{}, "a\nb\n", "a\nb\n"},
+		// a carriage return on its own is redrawing something
+		{"lf lone return", lfOnly{}, "50%\r100%\r\n", "50%\r100%\n"},
+		{"lf return at the end", lfOnly{}, "a\r", "a\r"},
+		{"crlf nothing", crlf{}, "", ""},
+		{"crlf", crlf{}, "a\nb\n", "a\r\nb\r\n"},
+		{"crlf blank lines", crlf{}, "\n\n", "\r\n\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := transform.String(tt.t, tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+
+			// a \r\n split between reads is still a \r\n
+			r := transform.NewReader(iotest.OneByteReader(strings.NewReader(tt.input)), tt.t)
+			split, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(split) != tt.want {
+				t.Errorf("a byte at a time got %q, want %q", split, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,681 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+var (
+	jitter         jitterFlag
+	typist         = flag.String("typist", "", "type like a touch typist on a keyboard layout around the base delay: qwerty, dvorak, or colemak")
+	sentencePause  = flag.Duration("sentence-pause", 0, "pause this much longer after the end of a sentence or a colon")
+	commaPause     = flag.Duration("comma-pause", 0, "pause this much longer after a comma or a semicolon")
+	delayTable     = flag.String("delay-table", "", "a JSON file saying exactly how long to wait for particular runes")
+	whitespace     = flag.Duration("whitespace-delay", 0, "wait exactly this long after spaces, tabs, and newlines instead of treating them like everything else")
+	paragraphPause = flag.Duration("paragraph-pause", 0, "pause this much longer after every blank line")
+	thinkChance    = flag.Float64("think", 0, "the chance of stopping to think between words, from 0 to 1")
+	thinkMin       = flag.Duration("think-min", 1*time.Second, "the shortest time spent thinking")
+	thinkMax       = flag.Duration("think-max", 4*time.Second, "the longest time spent thinking")
+	baud           = flag.Float64("baud", 0, "write like a modem at this baud rate, like 300, 1200, 2400, 9600, or 14400")
+	wpm            = flag.Float64("wpm", 0, "write this many words per minute, where a word is five characters")
+	morse          = flag.Float64("morse", 0, "keep International Morse timing at this many words per minute")
+	bpm            = flag.Float64("bpm", 0, "land on the beat at this many beats per minute")
+	subdivision    = flag.Float64("subdivision", 1, "with -bpm, the number of notes to a beat. 4 is sixteenth notes")
+	frequency      = flag.Bool("frequency", false, "be quick with common letters and slow with rare ones, between the fastest and slowest delays bits would give")
+	frequencies    = flag.String("frequency-table", "", "with -frequency, a JSON file of character frequencies to use instead of English")
+	delayFD        = flag.String("delay-fd", "", "read delays from this file descriptor or named pipe, one per token, as durations (150ms) or seconds (0.15). once it runs out, go back to being patient as usual")
+	fastMatch      = flag.String("fast-match", "", "write anything matching this regular expression immediately, like timestamps or prompts")
+	perByte        = flag.Bool("per-byte", false, "be patient about every byte, even in the middle of a rune. the same as -unit byte")
+	chat           = flag.Float64("tokens", 0, "stream pieces of words in bursts like a chat model, at about this many tokens a second")
+	letterDelay    = flag.Duration("letter-delay", 0, "wait exactly this long after letters")
+	digitDelay     = flag.Duration("digit-delay", 0, "wait exactly this long after digits")
+	punctDelay     = flag.Duration("punct-delay", 0, "wait exactly this long after punctuation and symbols like $ and +")
+	spaceDelay     = flag.Duration("space-delay", 0, "wait exactly this long after whitespace")
+	controlDelay   = flag.Duration("control-delay", 0, "wait exactly this long after control characters")
+	otherDelay     = flag.Duration("other-delay", 0, "wait exactly this long after anything that isn't a letter, digit, punctuation, whitespace, or control character")
+	shiftPenalty   = flag.Duration("shift-penalty", 0, "wait this much longer before uppercase letters and anything else typed with shift, on the -typist layout or qwerty")
+	encodedLength  = flag.Bool("scale-by-bytes", false, "wait once for every byte it takes to encode a rune, so CJK and emoji take longer")
+	timestamps     = flag.Bool("timestamps", false, "replay lines at the pace of the timestamps they start with, like a log. unless -unit is set, every line is written all at once")
+	timestampSpeed = flag.Float64("timestamp-speed", 1, "with -timestamps, replay this many times as fast as the original")
+	drunk          = flag.Int("drunk", 0, "fall apart over this many runes: delays get erratic, thinking between words gets likely, and typos get common")
+	lineSpeed      = flag.String("line-speed", "", "go a random speed on every line, somewhere in a range of multipliers like 0.5-2")
+	network        = flag.String("network", "", "write like it's going over a bad connection: "+strings.Join(slow.Networks(), ", "))
+	lineLength     = flag.Int("line-length", 0, "wait longer on lines longer than this many runes, and less on shorter ones")
+	lineExponent   = flag.Float64("line-length-exponent", 1, "with -line-length, how much length matters. 1 is in proportion, and -1 hurries through long lines instead")
+	memory         = flag.Float64("muscle-memory", 0, "go through anything that's already come up recently this many times as patiently, like 0.2 for five times as fast")
+	memoryMin      = flag.Int("muscle-memory-min", 8, "with -muscle-memory, the fewest runes in a row worth remembering")
+	memoryWindow   = flag.Int("muscle-memory-window", 4096, "with -muscle-memory, how many bytes to remember")
+	binary         = flag.Bool("binary", false, "pass bytes that aren't UTF-8 through untouched, one at a time, instead of replacing them with U+FFFD")
+	wideFactor     = flag.Float64("wide", 1, "wait this many times as long for characters two columns wide, like CJK and emoji. 2 paces them like two characters")
+	graphemes      = flag.String("graphemes", "auto", "write whole grapheme clusters at once, so emoji and accents never show up half-drawn: on, off, or auto for only on terminals, reading input that isn't still on its way. -unit always wins")
+	human          = flag.Bool("human", false, "type like a person around the base delay, in bursts, with hesitations, getting tired over time")
+	seed           = flag.Int64("seed", 0, "seed jitter, typos, thinking, and everything else random with this number, to get the same delays and mistakes every time. without one, a seed is picked and printed")
+	ramp           = flag.String("ramp", "", "gradually change speed with an easing curve: linear, ease-in, ease-out, or ease-in-out")
+	rampFrom       = flag.Float64("ramp-from", 4, "how many times as patient to be at the start of a ramp")
+	rampTo         = flag.Float64("ramp-to", 0.25, "how many times as patient to be at the end of a ramp")
+	rampWindow     = flag.Int("ramp-window", 0, "the number of runes to ramp over. if zero, ramp over everything, which means reading everything first")
+	stddev         = flag.Duration("stddev", 0, "draw every delay from a normal distribution around the base delay with this standard deviation, instead of using bits")
+)
+
+// every flag that sets a delay for a whole class of characters
+var classFlags = map[string]slow.Class{
+	"letter-delay":  slow.ClassLetter,
+	"digit-delay":   slow.ClassDigit,
+	"punct-delay":   slow.ClassPunctuation,
+	"space-delay":   slow.ClassSpace,
+	"control-delay": slow.ClassControl,
+	"other-delay":   slow.ClassOther,
+}
+
+func init() {
+	flag.Var(&jitter, "jitter", "add up to this much random noise to every delay, either as a duration (50ms) or a percentage of the delay (20%)")
+}
+
+// returns true if being patient means knowing exactly how many runes there are
+// before starting
+func needsLength() bool {
+	return *ramp != "" && *rampWindow == 0
+}
+
+// whether to wait for every rune in a token. streaming pieces of words waits
+// once per piece, and replaying timestamps has to see the end of every line.
+func scaleByLength() bool {
+	if *timestamps && !isSet("unit") {
+		return true
+	}
+	return *scale && *chat == 0
+}
+
+// every option for being patient the way the flags say to. the Patience in
+// the options comes back too, for anything that wants to reset it.
+func optionsFromFlags(length int) (slow.Patience, []slow.Option, error) {
+	patience, tokenizer, err := patienceFromFlags(length)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts := []slow.Option{
+		slow.WithPatience(patience),
+		slow.WithTokenizer(tokenizer),
+		slow.WithLengthScaling(scaleByLength()),
+	}
+	if *bpm > 0 {
+		opts = append(opts, slow.WithAbsoluteTime())
+	}
+	if *instantEscapes {
+		opts = append(opts, slow.WithInstantEscapes())
+	}
+	if *lineLength > 0 {
+		opts = append(opts, slow.WithLineBuffering())
+	}
+	if *fastMatch != "" {
+		re, err := regexp.Compile(*fastMatch)
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, slow.WithFastMatch(re))
+	}
+	if *typos > 0 || *drunk > 0 {
+		t, err := typosFromFlags()
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, slow.WithTypos(t))
+	}
+	return patience, opts, nil
+}
+
+// figure out exactly how patient to be from the command line. length is the
+// number of runes in the input, if it's known.
+func patienceFromFlags(length int) (slow.Patience, slow.Tokenizer, error) {
+	p, err := presetFromFlags()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	patience, err := basePatience(p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if *delayFD != "" {
+		r, err := sharedDelays()
+		if err != nil {
+			return nil, nil, err
+		}
+		patience = slow.Stream(r, patience)
+	}
+
+	if *wideFactor < 0 {
+		return nil, nil, fmt.Errorf("invalid wide character factor: %v", *wideFactor)
+	}
+	if *wideFactor != 1 {
+		patience = slow.Wide(patience, *wideFactor)
+	}
+
+	if *encodedLength {
+		patience = slow.Encoded(patience)
+	}
+
+	if *timestamps {
+		if *timestampSpeed <= 0 {
+			return nil, nil, fmt.Errorf("invalid timestamp speed: %v", *timestampSpeed)
+		}
+		if !isSet("unit") {
+			patience = slow.PatienceFunc(func(rune) time.Duration { return 0 })
+		}
+		patience = slow.Timestamps(patience, *timestampSpeed, nil)
+	}
+
+	classes := map[slow.Class]time.Duration{}
+	for name, class := range classFlags {
+		if isSet(name) {
+			classes[class] = flag.Lookup(name).Value.(flag.Getter).Get().(time.Duration)
+		}
+	}
+	if len(classes) > 0 {
+		patience = slow.ByClass(patience, classes)
+	}
+
+	if *delayTable != "" {
+		t, err := readDelayTable(*delayTable)
+		if err != nil {
+			return nil, nil, err
+		}
+		patience = slow.Table(patience, t)
+	}
+	if isSet("whitespace-delay") {
+		patience = slow.Whitespace(patience, *whitespace)
+	}
+	if *sentencePause != 0 || *commaPause != 0 {
+		patience = slow.PauseAfter(patience, slow.Punctuation(*sentencePause, *commaPause))
+	}
+	if *paragraphPause != 0 {
+		patience = slow.ParagraphPause(patience, *paragraphPause)
+	}
+
+	if *shiftPenalty != 0 {
+		var layout *slow.Layout
+		if *typist != "" {
+			if layout, err = layoutFor(*typist); err != nil {
+				return nil, nil, err
+			}
+		}
+		patience = slow.ShiftPenalty(patience, layout, *shiftPenalty)
+	}
+
+	if *memory < 0 || *memoryMin < 1 || *memoryWindow < 1 {
+		return nil, nil, fmt.Errorf("invalid muscle memory: %v times as patient, at least %d runes, remembering %d bytes", *memory, *memoryMin, *memoryWindow)
+	}
+	if isSet("muscle-memory") {
+		patience = slow.MuscleMemory(patience, *memoryWindow, *memoryMin, *memory)
+	}
+
+	if *lineLength < 0 {
+		return nil, nil, fmt.Errorf("invalid line length: %d", *lineLength)
+	}
+	if *lineLength > 0 {
+		patience = slow.LineLength(patience, *lineLength, *lineExponent)
+	}
+
+	if *lineSpeed != "" {
+		slowest, fastest, err := parseSpeedRange(*lineSpeed)
+		if err != nil {
+			return nil, nil, err
+		}
+		patience = slow.PerLine(patience, slowest, fastest, newRand())
+	}
+
+	if *drunk < 0 {
+		return nil, nil, fmt.Errorf("invalid number of runes to get drunk over: %d", *drunk)
+	}
+	if *drunk > 0 {
+		patience = slow.Drunk(patience, *drunk, newRand())
+	}
+
+	if *thinkChance > 0 {
+		patience = slow.Think(patience, *thinkChance, *thinkMin, *thinkMax, newRand())
+	}
+
+	if *ramp != "" {
+		ease, err := easingFor(*ramp)
+		if err != nil {
+			return nil, nil, err
+		}
+		window := *rampWindow
+		if window == 0 {
+			window = length
+		}
+		patience = slow.Ramp(patience, *rampFrom, *rampTo, window, ease)
+	}
+
+	if jitter.set {
+		p.Jitter = jitter.amount
+	}
+	if p.Jitter != 0 {
+		patience = slow.Jitter(patience, p.Jitter, newRand())
+	}
+	if jitter.percent != 0 {
+		patience = slow.JitterFraction(patience, jitter.percent/100, newRand())
+	}
+
+	tokenizer := p.Tokenizer
+	if *chat > 0 && !isSet("unit") && !*perByte {
+		tokenizer = slow.WordPieces
+	}
+	if *timestamps && !isSet("unit") && !*perByte {
+		tokenizer = slow.Lines
+	}
+	if tokenizer == nil {
+		tokenizer, _ = tokenizerFor("rune")
+	}
+	return patience, tokenizer, nil
+}
+
+// how sloppy being drunk gets, unless there's a typo rate already
+const drunkTypos = 0.15
+
+// the strategy everything else is piled on top of
+func basePatience(p slow.Preset) (slow.Patience, error) {
+	switch {
+	case *network != "":
+		n, ok := slow.LookupNetwork(*network)
+		if !ok {
+			return nil, fmt.Errorf("unknown network: %s", *network)
+		}
+		return n.Patience(newRand()), nil
+	case *baud < 0:
+		return nil, fmt.Errorf("invalid baud rate: %v", *baud)
+	case *baud > 0:
+		return slow.Baud(*baud), nil
+	case *wpm < 0:
+		return nil, fmt.Errorf("invalid words per minute: %v", *wpm)
+	case *wpm > 0:
+		return slow.WPM(*wpm), nil
+	case *bpm < 0 || (*bpm > 0 && *subdivision <= 0):
+		return nil, fmt.Errorf("invalid tempo: %v bpm, %v notes to a beat", *bpm, *subdivision)
+	case *bpm > 0:
+		return slow.Metronome(*bpm, *subdivision), nil
+	case *morse < 0:
+		return nil, fmt.Errorf("invalid words per minute: %v", *morse)
+	case *morse > 0:
+		return slow.Morse(*morse), nil
+	case *chat < 0:
+		return nil, fmt.Errorf("invalid tokens per second: %v", *chat)
+	case *chat > 0:
+		return slow.Chat(*chat, newRand()), nil
+	case *frequency || *frequencies != "":
+		freqs := slow.English
+		if *frequencies != "" {
+			var err error
+			if freqs, err = readFrequencies(*frequencies); err != nil {
+				return nil, err
+			}
+		}
+		slowest := p.Base + p.Step*time.Duration((1<<p.Bits)-1)
+		return slow.Frequency(p.Base, slowest, freqs), nil
+	case *human:
+		return slow.Human(p.Base, newRand()), nil
+	case *typist != "":
+		layout, err := layoutFor(*typist)
+		if err != nil {
+			return nil, err
+		}
+		return slow.Typist(layout, p.Base), nil
+	case *stddev > 0:
+		return slow.Gaussian(p.Base, *stddev, newRand()), nil
+	default:
+		return slow.BePatientHashed(p.Bits, p.Base, p.Step, p.Hash), nil
+	}
+}
+
+// figure out how to make mistakes from the command line
+func typosFromFlags() (slow.Typos, error) {
+	t := slow.Typos{
+		Rate:   *typos,
+		Notice: *typoNotice,
+		Rand:   newRand(),
+	}
+	if *drunk > 0 {
+		// the drunker the sloppier
+		if !isSet("typos") {
+			t.Rate = drunkTypos
+		}
+		t.Ramp = *drunk
+	}
+	if *typist != "" {
+		layout, err := layoutFor(*typist)
+		if err != nil {
+			return t, err
+		}
+		t.Layout = layout
+	}
+
+	switch *typoErase {
+	case "backspace":
+		t.Erase = slow.EraseBackspace
+	case "csi":
+		t.Erase = slow.EraseCSI
+	default:
+		return t, fmt.Errorf("unknown way to erase typos: %s", *typoErase)
+	}
+	return t, nil
+}
+
+func readDelayTable(filename string) (*slow.DelayTable, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	t, err := slow.ParseDelayTable(f)
+	if err != nil {
+		return nil, fmt.Errorf("error reading delay table %s: %s", filename, err)
+	}
+	return t, nil
+}
+
+// parse a range of speeds like "0.5-2"
+func parseSpeedRange(s string) (float64, float64, error) {
+	parts := strings.SplitN(s, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid range of speeds: %s", s)
+	}
+	slowest, err := strconv.ParseFloat(strings.TrimSuffix(parts[0], "x"), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid range of speeds: %s", s)
+	}
+	fastest, err := strconv.ParseFloat(strings.TrimSuffix(parts[1], "x"), 64)
+	if err != nil || slowest <= 0 || fastest < slowest {
+		return 0, 0, fmt.Errorf("invalid range of speeds: %s", s)
+	}
+	return slowest, fastest, nil
+}
+
+// the delays from -delay-fd. patience gets figured out again for every stream
+// and everyone who connects to a server, but there's only one -delay-fd to
+// read from, so it's only opened once and everyone shares it.
+var (
+	delaysMu sync.Mutex
+	delays   *delayLines
+)
+
+func sharedDelays() (io.Reader, error) {
+	delaysMu.Lock()
+	defer delaysMu.Unlock()
+
+	if delays == nil {
+		f, err := openDelays(*delayFD)
+		if err != nil {
+			return nil, err
+		}
+		delays = &delayLines{r: bufio.NewReader(f)}
+	}
+	return delays, nil
+}
+
+// delays shared between everyone who reads them. reads never return more than
+// one line, so nobody reads ahead into a delay that should have been someone
+// else's.
+type delayLines struct {
+	mu      sync.Mutex
+	r       *bufio.Reader
+	pending []byte
+	err     error
+}
+
+func (d *delayLines) Read(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for len(d.pending) == 0 {
+		if d.err != nil {
+			return 0, d.err
+		}
+		d.pending, d.err = d.r.ReadBytes('\n')
+	}
+	n := copy(p, d.pending)
+	d.pending = d.pending[n:]
+	return n, nil
+}
+
+// open a stream of delays. numbers are file descriptors someone left open,
+// anything else is a path.
+func openDelays(name string) (*os.File, error) {
+	fd, err := strconv.ParseUint(name, 10, 0)
+	if err != nil {
+		return os.Open(name)
+	}
+
+	f := os.NewFile(uintptr(fd), "delay-fd")
+	if f == nil {
+		return nil, fmt.Errorf("invalid file descriptor: %s", name)
+	}
+	return f, nil
+}
+
+func readFrequencies(filename string) (map[rune]float64, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	freqs, err := slow.ParseFrequencies(f)
+	if err != nil {
+		return nil, fmt.Errorf("error reading frequencies %s: %s", filename, err)
+	}
+	return freqs, nil
+}
+
+// a source of randomness. every strategy gets its own, all seeded from the same
+// seed, so the same seed means the same delays and the same mistakes every
+// time. without a seed, one gets picked and printed so whatever happened can
+// happen again.
+//
+// newRand is safe to call from any goroutine, since everyone who connects to a
+// server gets their own.
+func newRand() *rand.Rand {
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+
+	if !isSet("seed") && sources == 0 {
+		*seed = time.Now().UnixNano()
+		fmt.Fprintf(os.Stderr, "seed: %d\n", *seed)
+	}
+	sources++
+	return rand.New(rand.NewSource(*seed + sources))
+}
+
+// how many random sources newRand has handed out
+var (
+	sourcesMu sync.Mutex
+	sources   int64
+)
+
+func easingFor(name string) (slow.Easing, error) {
+	switch name {
+	case "linear":
+		return slow.Linear, nil
+	case "ease-in":
+		return slow.EaseIn, nil
+	case "ease-out":
+		return slow.EaseOut, nil
+	case "ease-in-out":
+		return slow.EaseInOut, nil
+	default:
+		return nil, fmt.Errorf("unknown easing: %s", name)
+	}
+}
+
+func layoutFor(name string) (*slow.Layout, error) {
+	switch name {
+	case "qwerty":
+		return slow.QWERTY, nil
+	case "dvorak":
+		return slow.Dvorak, nil
+	case "colemak":
+		return slow.Colemak, nil
+	default:
+		return nil, fmt.Errorf("unknown layout: %s", name)
+	}
+}
+
+// build a preset out of the command line. flags that are set explicitly always
+// win over a named preset.
+func presetFromFlags() (slow.Preset, error) {
+	name := *unit
+	if !isSet("unit") && !*binary {
+		on, err := graphemesOn()
+		if err != nil {
+			return slow.Preset{}, err
+		}
+		if on {
+			name = "grapheme"
+		}
+	}
+	if *perByte {
+		name = "byte"
+	}
+	tokenizer, err := tokenizerFor(name)
+	if err != nil {
+		return slow.Preset{}, err
+	}
+	runeHash, err := hashFor(*hash)
+	if err != nil {
+		return slow.Preset{}, err
+	}
+	p := slow.Preset{Base: *initial, Step: *step, Bits: *bits, Hash: runeHash, Tokenizer: tokenizer}
+
+	if *preset == "" {
+		return p, nil
+	}
+	named, ok := slow.LookupPreset(*preset)
+	if !ok {
+		return slow.Preset{}, fmt.Errorf("unknown preset: %s", *preset)
+	}
+
+	if !isSet("base") {
+		p.Base = named.Base
+	}
+	if !isSet("step") {
+		p.Step = named.Step
+	}
+	if !isSet("bits") {
+		p.Bits = named.Bits
+	}
+	if !isSet("hash") {
+		p.Hash = named.Hash
+	}
+	if !isSet("unit") && !*perByte && named.Tokenizer != nil {
+		p.Tokenizer = named.Tokenizer
+	}
+	p.Jitter = named.Jitter
+	return p, nil
+}
+
+// whether to write whole grapheme clusters when nobody's said what to be
+// patient about
+func graphemesOn() (bool, error) {
+	switch *graphemes {
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	case "auto":
+		// nobody can tell a cluster is over until the next one starts, and
+		// input that's still on its way might not have a next one for a
+		// while
+		return isTerminal(out) && !liveInput(), nil
+	default:
+		return false, fmt.Errorf("unknown graphemes setting: %s", *graphemes)
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+func isRegular(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode().IsRegular()
+}
+
+func hashFor(name string) (slow.RuneHash, error) {
+	switch name {
+	case "none":
+		return nil, nil
+	case "fnv":
+		return slow.FNV, nil
+	case "crc32":
+		return slow.CRC32, nil
+	default:
+		return nil, fmt.Errorf("unknown hash: %s", name)
+	}
+}
+
+func tokenizerFor(unit string) (slow.Tokenizer, error) {
+	switch unit {
+	case "rune":
+		if *binary {
+			return slow.RawRunes, nil
+		}
+		return slow.Runes, nil
+	case "word":
+		return slow.Words, nil
+	case "line":
+		return slow.Lines, nil
+	case "grapheme":
+		return slow.Graphemes, nil
+	case "byte":
+		return slow.Bytes, nil
+	default:
+		return nil, fmt.Errorf("unknown unit: %s", unit)
+	}
+}
+
+// a flag that's either a duration or a percentage
+type jitterFlag struct {
+	set     bool
+	amount  time.Duration
+	percent float64
+}
+
+func (j *jitterFlag) String() string {
+	if j.percent != 0 {
+		return strconv.FormatFloat(j.percent, 'f', -1, 64) + "%"
+	}
+	return j.amount.String()
+}
+
+func (j *jitterFlag) Set(s string) error {
+	j.set = true
+	if strings.HasSuffix(s, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		if err != nil || percent < 0 {
+			return fmt.Errorf("invalid percentage: %s", s)
+		}
+		j.amount, j.percent = 0, percent
+		return nil
+	}
+
+	amount, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	j.amount, j.percent = amount, 0
+	return nil
+}
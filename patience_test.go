@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestDelayLines(t *testing.T) {
+	d := &delayLines{r: bufio.NewReader(strings.NewReader("1ms\n2ms\n3ms\n4ms"))}
+
+	// two Streams sharing the same delays get every one of them between them,
+	// and none twice
+	a, b := slow.Stream(d, nil), slow.Stream(d, nil)
+	got := []time.Duration{a.Delay('a'), b.Delay('b'), a.Delay('a'), b.Delay('b'), a.Delay('a')}
+	want := []time.Duration{time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond, 4 * time.Millisecond, 0}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("waited %v, want %v", got, want)
+		}
+	}
+}
+
+func TestDelayLinesConcurrently(t *testing.T) {
+	var lines strings.Builder
+	for i := 1; i <= 100; i++ {
+		lines.WriteString("1ms\n")
+	}
+	d := &delayLines{r: bufio.NewReader(strings.NewReader(lines.String()))}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var total time.Duration
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s := slow.Stream(d, nil)
+			for j := 0; j < 30; j++ {
+				delay := s.Delay('a')
+				mu.Lock()
+				total += delay
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if total != 100*time.Millisecond {
+		t.Errorf("waited %v in all, want %v", total, 100*time.Millisecond)
+	}
+}
+
+func TestPresetFromFlags(t *testing.T) {
+	defer func(old string) { *preset = old }(*preset)
+
+	tests := []struct {
+		name string
+		base time.Duration
+		err  string
+	}{
+		{"", time.Second, ""},
+		{"teletype", 100 * time.Millisecond, ""},
+		{"nope", 0, "unknown preset: nope"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*preset = tt.name
+			p, err := presetFromFlags()
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("got error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Base != tt.base {
+				t.Errorf("base delay is %v, want %v", p.Base, tt.base)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"math/rand"
+)
+
+// split into lines that keep their newlines. the last line gets one if it
+// didn't have one, so it doesn't run into whatever ends up after it.
+func lines(data []byte) [][]byte {
+	var lines [][]byte
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			lines = append(lines, append(data[:len(data):len(data)], '\n'))
+			break
+		}
+		lines, data = append(lines, data[:i+1]), data[i+1:]
+	}
+	return lines
+}
+
+// every line, in any order at all
+func shuffleLines(data []byte, rnd *rand.Rand) []byte {
+	lines := lines(data)
+	rnd.Shuffle(len(lines), func(i, j int) {
+		lines[i], lines[j] = lines[j], lines[i]
+	})
+	return bytes.Join(lines, nil)
+}
+
+// last line first
+func reverseLines(data []byte) []byte {
+	lines := lines(data)
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return bytes.Join(lines, nil)
+}
+
+// everything backwards, one rune at a time, like it's being read in a mirror. a
+// newline at the very end stays at the end.
+func reverseRunes(data []byte) []byte {
+	end := ""
+	if bytes.HasSuffix(data, []byte("\n")) {
+		data, end = data[:len(data)-1], "\n"
+	}
+
+	runes := bytes.Runes(data)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return []byte(string(runes) + end)
+}
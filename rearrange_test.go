@@ -0,0 +1,73 @@
+package main
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestShuffleLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		// every line that should come out, in order
+		lines []string
+	}{
+		{"nothing", "", nil},
+		{"one line", "a\n", []string{"a\n"}},
+		// the last line gets a newline so it doesn't run into anything
+		{"no newline at the end", "a\nb", []string{"a\n", "b\n"}},
+		{"blank lines", "a\n\nb\n", []string{"\n", "a\n", "b\n"}},
+		{"lots", "a\nb\nc\nd\ne\nf\n", []string{"a\n", "b\n", "c\n", "d\n", "e\n", "f\n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := shuffleLines([]byte(tt.input), rand.New(rand.NewSource(1)))
+
+			// every line is still there, just somewhere else
+			var lines []string
+			for _, line := range strings.SplitAfter(string(got), "\n") {
+				if line != "" {
+					lines = append(lines, line)
+				}
+			}
+			sort.Strings(lines)
+			if strings.Join(lines, "") != strings.Join(tt.lines, "") {
+				t.Errorf("shuffled %q into %q", tt.input, got)
+			}
+
+			// and the same seed shuffles the same way
+			if again := shuffleLines([]byte(tt.input), rand.New(rand.NewSource(1))); string(again) != string(got) {
+				t.Errorf("the same seed shuffled into %q and %q", got, again)
+			}
+		})
+	}
+}
+
+func TestReverse(t *testing.T) {
+	tests := []struct {
+		name    string
+		reverse func([]byte) []byte
+		input   string
+		want    string
+	}{
+		{"lines nothing", reverseLines, "", ""},
+		{"lines", reverseLines, "a\nb\nc\n", "c\nb\na\n"},
+		{"lines without a newline at the end", reverseLines, "a\nb", "b\na\n"},
+		{"runes nothing", reverseRunes, "", ""},
+		{"runes", reverseRunes, "abc", "cba"},
+		// a newline at the very end stays there
+		{"runes newline at the end", reverseRunes, "ab\ncd\n", "dc\nba\n"},
+		{"runes multibyte", reverseRunes, "aé世", "世éa"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.reverse([]byte(tt.input)); string(got) != tt.want {
+				t.Errorf("reversed %q into %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// aslap record saves how long everything took to arrive, in the same timing
+// format script -t writes, so aslap -replay script can play it back exactly.
+// everything read is written straight through.
+func record(args []string) int {
+	flags := flag.NewFlagSet("record", flag.ExitOnError)
+	typescript := flags.String("typescript", "", "save everything read to this file too, to replay along with the timing file")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: %s record [flags] timing-file\n\n", os.Args[0])
+		fmt.Fprintf(flags.Output(), "read stdin, or what's typed at a terminal, and write it straight to stdout while\n")
+		fmt.Fprintf(flags.Output(), "saving when everything arrived. play it back again with:\n\n")
+		fmt.Fprintf(flags.Output(), "    %s -replay script -replay-timing timing-file typescript\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	timing, err := os.Create(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer timing.Close()
+
+	dst := io.Writer(os.Stdout)
+	if *typescript != "" {
+		f, err := os.Create(*typescript)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer f.Close()
+		dst = io.MultiWriter(os.Stdout, f)
+	}
+
+	src := io.Reader(os.Stdin)
+	if isTerminal(os.Stdin) {
+		k, err := startEcho()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer k.Close()
+		src = k
+	}
+
+	if err := recordTiming(dst, timing, src); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// copy src to dst, writing how long every read took to show up, and how big
+// it was, to timing
+func recordTiming(dst, timing io.Writer, src io.Reader) error {
+	buf := make([]byte, 32*1024)
+	last := time.Now()
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			now := time.Now()
+			if _, err := fmt.Fprintf(timing, "%.6f %d\n", now.Sub(last).Seconds(), n); err != nil {
+				return err
+			}
+			if _, err := dst.Write(buf[:n]); err != nil {
+				return err
+			}
+			last = now
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+	"golang.org/x/text/transform"
+)
+
+// a way to write down a show instead of playing it
+type renderer struct {
+	render func(plan slow.Plan, dst io.Writer) error
+	// any flags of its own, if it has them
+	flags func()
+}
+
+var renderers = map[string]renderer{
+	"cast":   {render: renderCast, flags: castFlags},
+	"script": {render: renderScript, flags: scriptFlags},
+	"ttyrec": {render: renderTtyrec},
+}
+
+func rendererNames() []string {
+	var names []string
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// aslap render works out exactly when everything would be written, without
+// waiting for any of it, and writes down a recording that plays it back at
+// that pace. every flag for being patient works the same way it does
+// everywhere else, except -wave and -schedule, which it refuses.
+func render(args []string) int {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s render %s [flags] [file or url ...]\n\n", os.Args[0], strings.Join(rendererNames(), "|"))
+		fmt.Fprintf(flag.CommandLine.Output(), "record files, or whatever's piped in, as slowly as possible, right away\n\n")
+		flag.PrintDefaults()
+	}
+	if len(args) == 0 {
+		flag.Usage()
+		return 2
+	}
+	r, ok := renderers[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "can't render %s. try %s\n", args[0], strings.Join(rendererNames(), ", "))
+		return 2
+	}
+	if r.flags != nil {
+		r.flags()
+	}
+	flag.CommandLine.Parse(args[1:])
+
+	// both of them change speed as time goes by, and rendering doesn't take
+	// any time at all
+	if *wave > 0 || *schedule != "" {
+		fmt.Fprintln(os.Stderr, "can't render with -wave or -schedule. they follow the clock, and rendering never waits for it")
+		return 2
+	}
+
+	sh, err := newShow(flag.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	opts, err := sh.options()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	content := sh.open()
+	plan, err := slow.Record(content, opts...)
+	content.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	dst := out
+	if *outputPath != "" {
+		f, _, err := openOutput(*outputPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer f.Close()
+		dst = f
+	}
+
+	if err := r.render(onTerminal(plan), dst); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// everything written the way a terminal would have shown it, with every
+// newline going back to the start of the line too
+func onTerminal(plan slow.Plan) slow.Plan {
+	shown := slow.Plan{Total: plan.Total}
+	for _, c := range plan.Chunks {
+		data, _, err := transform.Bytes(transform.Chain(lfOnly{}, crlf{}), c.Data)
+		if err != nil {
+			data = c.Data
+		}
+		shown.Chunks = append(shown.Chunks, slow.Chunk{Data: data, Delay: c.Delay})
+	}
+	return shown
+}
+
+var castWidth, castHeight *int
+
+func castFlags() {
+	castWidth = flag.Int("width", 80, "how many columns wide the cast's terminal is")
+	castHeight = flag.Int("height", 24, "how many rows tall the cast's terminal is")
+}
+
+func renderCast(plan slow.Plan, dst io.Writer) error {
+	return plan.WriteCast(dst, *castWidth, *castHeight)
+}
+
+func renderTtyrec(plan slow.Plan, dst io.Writer) error {
+	return plan.WriteTtyrec(dst, time.Now())
+}
+
+var scriptTiming *string
+
+func scriptFlags() {
+	scriptTiming = flag.String("timing", "", "where to write the timing file that goes with the typescript. play them back with scriptreplay -t timing typescript")
+}
+
+// the typescript is the output, and the timing goes off to the side
+func renderScript(plan slow.Plan, dst io.Writer) error {
+	if *scriptTiming == "" {
+		return errors.New("rendering a typescript needs somewhere to write its -timing file")
+	}
+	timing, err := os.Create(*scriptTiming)
+	if err != nil {
+		return err
+	}
+	if err := plan.WriteScript(dst, timing); err != nil {
+		timing.Close()
+		return err
+	}
+	return timing.Close()
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestOnTerminal(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"nothing", "", ""},
+		{"newline", "a\n", "a\r\n"},
+		// a \r\n is already going back to the start of the line
+		{"already crlf", "a\r\n", "a\r\n"},
+		{"lone return", "50%\r", "50%\r"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := slow.Plan{Chunks: []slow.Chunk{{Data: []byte(tt.data), Delay: time.Second}}, Total: time.Second}
+			shown := onTerminal(plan)
+			if len(shown.Chunks) != 1 || string(shown.Chunks[0].Data) != tt.want {
+				t.Fatalf("shown as %+v, want %q", shown.Chunks, tt.want)
+			}
+			if shown.Chunks[0].Delay != time.Second || shown.Total != time.Second {
+				t.Errorf("timing changed to %+v", shown)
+			}
+		})
+	}
+}
+
+func TestRenderCast(t *testing.T) {
+	defer func(w, h *int) { castWidth, castHeight = w, h }(castWidth, castHeight)
+
+	tests := []struct {
+		name          string
+		width, height int
+	}{
+		{"default", 80, 24},
+		{"wide", 200, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			castWidth, castHeight = &tt.width, &tt.height
+			plan := onTerminal(slow.Plan{Chunks: []slow.Chunk{{Data: []byte("hi\n"), Delay: time.Second}}, Total: time.Second})
+
+			var cast bytes.Buffer
+			if err := renderCast(plan, &cast); err != nil {
+				t.Fatal(err)
+			}
+
+			lines := bufio.NewScanner(&cast)
+			lines.Scan()
+			var header struct{ Width, Height int }
+			if err := json.Unmarshal(lines.Bytes(), &header); err != nil {
+				t.Fatal(err)
+			}
+			if header.Width != tt.width || header.Height != tt.height {
+				t.Errorf("cast is %dx%d, want %dx%d", header.Width, header.Height, tt.width, tt.height)
+			}
+
+			lines.Scan()
+			var event []interface{}
+			if err := json.Unmarshal(lines.Bytes(), &event); err != nil {
+				t.Fatal(err)
+			}
+			if len(event) != 3 || event[2] != "hi\r\n" {
+				t.Errorf("first event is %v, want hi\\r\\n", event)
+			}
+		})
+	}
+}
+
+// a plan that's been shown on a terminal, and what it says
+var rendered = onTerminal(slow.Plan{
+	Chunks: []slow.Chunk{{Data: []byte("hi\n"), Delay: 250 * time.Millisecond}, {Data: []byte("you"), Delay: time.Second}},
+	Total:  1250 * time.Millisecond,
+})
+
+func TestRenderTtyrec(t *testing.T) {
+	var rec bytes.Buffer
+	if err := renderTtyrec(rendered, &rec); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := slow.ParseTtyrec(&rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the pause at the end gets an empty frame of its own
+	want := []slow.Chunk{{Data: []byte("hi\r\n"), Delay: 250 * time.Millisecond}, {Data: []byte("you"), Delay: time.Second}, {}}
+	if len(got.Chunks) != len(want) {
+		t.Fatalf("got %d frames, want %d", len(got.Chunks), len(want))
+	}
+	for i := range want {
+		if string(got.Chunks[i].Data) != string(want[i].Data) || got.Chunks[i].Delay != want[i].Delay {
+			t.Errorf("frame %d is %q after %v, want %q after %v", i, got.Chunks[i].Data, got.Chunks[i].Delay, want[i].Data, want[i].Delay)
+		}
+	}
+}
+
+func TestRenderScript(t *testing.T) {
+	defer func(old *string) { scriptTiming = old }(scriptTiming)
+
+	t.Run("no timing", func(t *testing.T) {
+		none := ""
+		scriptTiming = &none
+		if err := renderScript(rendered, &bytes.Buffer{}); err == nil {
+			t.Error("rendered a typescript without anywhere to write its timing")
+		}
+	})
+
+	t.Run("timing", func(t *testing.T) {
+		timing := filepath.Join(t.TempDir(), "timing")
+		scriptTiming = &timing
+
+		var typescript bytes.Buffer
+		if err := renderScript(rendered, &typescript); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Open(timing)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		got, err := slow.ParseScriptTiming(f, &typescript)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the pause at the very end has nowhere to go
+		want := []slow.Chunk{{Data: []byte("hi\r\n"), Delay: 250 * time.Millisecond}, {Data: []byte("you")}}
+		if len(got.Chunks) != len(want) {
+			t.Fatalf("got %d chunks, want %d", len(got.Chunks), len(want))
+		}
+		for i := range want {
+			if string(got.Chunks[i].Data) != string(want[i].Data) || got.Chunks[i].Delay != want[i].Delay {
+				t.Errorf("chunk %d is %q after %v, want %q after %v", i, got.Chunks[i].Data, got.Chunks[i].Delay, want[i].Data, want[i].Delay)
+			}
+		}
+	})
+}
+
+func TestRenderRefusesClockFlags(t *testing.T) {
+	defer func(w time.Duration, s string) { *wave, *schedule = w, s }(*wave, *schedule)
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"wave", []string{"ttyrec", "-wave", "10s"}},
+		{"schedule", []string{"ttyrec", "-schedule", "speeds.txt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*wave, *schedule = 0, ""
+			if status := render(tt.args); status != 2 {
+				t.Errorf("render exited with %d, want 2", status)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"go.bug.st/serial"
+)
+
+var (
+	serialPort     = flag.String("serial", "", "read from this serial port, like /dev/ttyUSB0, instead of files")
+	serialBaud     = flag.Int("serial-baud", 9600, "with -serial, the baud rate to read at")
+	serialDataBits = flag.Int("serial-data-bits", 8, "with -serial, the number of data bits: 5, 6, 7, or 8")
+	serialParity   = flag.String("serial-parity", "none", "with -serial, the parity: none, odd, even, mark, or space")
+	serialStopBits = flag.String("serial-stop-bits", "1", "with -serial, the number of stop bits: 1, 1.5, or 2")
+)
+
+// open the serial port from the command line, set up the way it says
+func openSerial() (io.ReadCloser, error) {
+	mode := &serial.Mode{BaudRate: *serialBaud, DataBits: *serialDataBits}
+
+	switch *serialParity {
+	case "none":
+		mode.Parity = serial.NoParity
+	case "odd":
+		mode.Parity = serial.OddParity
+	case "even":
+		mode.Parity = serial.EvenParity
+	case "mark":
+		mode.Parity = serial.MarkParity
+	case "space":
+		mode.Parity = serial.SpaceParity
+	default:
+		return nil, fmt.Errorf("unknown parity: %s", *serialParity)
+	}
+
+	switch *serialStopBits {
+	case "1":
+		mode.StopBits = serial.OneStopBit
+	case "1.5":
+		mode.StopBits = serial.OnePointFiveStopBits
+	case "2":
+		mode.StopBits = serial.TwoStopBits
+	default:
+		return nil, fmt.Errorf("invalid number of stop bits: %s", *serialStopBits)
+	}
+
+	port, err := serial.Open(*serialPort, mode)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %s: %s", *serialPort, err)
+	}
+	return port, nil
+}
@@ -0,0 +1,389 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+// how long anyone has to say what they want, for anything that asks
+const requestTimeout = 30 * time.Second
+
+// a way to play a show for everyone who connects
+type server struct {
+	// where to listen when nobody says otherwise
+	listen string
+	// serve everyone who connects to l until ctx is done, and then wait for
+	// everyone to hang up
+	serve func(ctx context.Context, l net.Listener, sh *show) error
+	// any flags of its own, if it has them
+	flags func()
+}
+
+var servers = map[string]server{
+	"finger": {listen: ":7979", serve: serveFinger},
+	"gopher": {listen: ":7070", serve: serveGopher},
+	"http":   {listen: ":8080", serve: serveHTTP, flags: httpFlags},
+	"ssh":    {listen: ":2222", serve: serveSSH, flags: sshFlags},
+	"tcp":    {listen: ":2323", serve: serveTCP},
+	"telnet": {listen: ":2323", serve: serveTelnet},
+}
+
+func serverNames() []string {
+	var names []string
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// aslap serve plays the same thing for everyone who connects, every one of
+// them at their own pace. every flag for being patient works the same way it
+// does everywhere else.
+func serve(args []string) int {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s serve %s [flags] [file or url ...]\n\n", os.Args[0], strings.Join(serverNames(), "|"))
+		fmt.Fprintf(flag.CommandLine.Output(), "play files, or whatever's piped in, to everyone who connects\n\n")
+		flag.PrintDefaults()
+	}
+	if len(args) == 0 {
+		flag.Usage()
+		return 2
+	}
+	s, ok := servers[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "can't serve %s. try %s\n", args[0], strings.Join(serverNames(), ", "))
+		return 2
+	}
+
+	listen := flag.String("listen", s.listen, "the address to listen on, or unix:path for a unix socket")
+	drip := flag.Int("drip-bytes", 0, "hold everything until there's this many bytes, and then send them all at once")
+	stall := flag.Duration("stall", 0, "wait this long after someone connects before sending anything")
+	limit := flag.Duration("max-duration", 0, "hang up on everyone after this long, whether they've seen everything or not")
+	if s.flags != nil {
+		s.flags()
+	}
+	flag.CommandLine.Parse(args[1:])
+	if *drip < 0 || *stall < 0 || *limit < 0 {
+		fmt.Fprintln(os.Stderr, "-drip-bytes, -stall, and -max-duration can't be negative")
+		return 2
+	}
+
+	sh, err := newShow(flag.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	sh.drip, sh.stall, sh.limit = *drip, *stall, *limit
+	// check the flags once, before anyone's connected
+	if _, err := sh.options(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	l, err := listenOn(*listen)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	// closing a unix socket cleans up after it, too
+	defer l.Close()
+	fmt.Fprintf(os.Stderr, "serving %s on %s\n", args[0], l.Addr())
+
+	// hang up on everyone nicely on the way out
+	ctx, stop := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		fmt.Fprintln(os.Stderr, "shutting down")
+		stop()
+	}()
+
+	if err := s.serve(ctx, l, sh); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// listen on a TCP address like :8080, or a unix socket if it looks like a
+// path. a socket left behind by a server that's gone away is replaced, but one
+// that's still being listened on is left alone.
+func listenOn(addr string) (net.Listener, error) {
+	network := "tcp"
+	if strings.HasPrefix(addr, "unix:") {
+		network, addr = "unix", strings.TrimPrefix(addr, "unix:")
+	} else if strings.ContainsRune(addr, '/') {
+		network = "unix"
+	}
+
+	if network == "unix" {
+		if stat, err := os.Stat(addr); err == nil && stat.Mode()&os.ModeSocket != 0 {
+			if conn, err := net.Dial("unix", addr); err == nil {
+				conn.Close()
+				return nil, fmt.Errorf("someone's already listening on %s", addr)
+			}
+			os.Remove(addr)
+		}
+	}
+	return net.Listen(network, addr)
+}
+
+// what everyone gets to see. files are opened again for everyone, so they're
+// always up to date, but anything piped in can only be read once, so it's read
+// before anyone connects.
+type show struct {
+	names []string
+	piped []byte
+
+	drip  int
+	stall time.Duration
+	limit time.Duration
+}
+
+func newShow(names []string) (*show, error) {
+	if len(names) == 0 || (len(names) == 1 && names[0] == "-") {
+		data, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		return &show{piped: data}, nil
+	}
+	for _, name := range names {
+		if name == "-" {
+			return nil, errors.New("can't serve standard input along with anything else")
+		}
+	}
+	return &show{names: names}, nil
+}
+
+// everything there is to see, starting from the top
+func (sh *show) open() io.ReadCloser {
+	if sh.names == nil {
+		return ioutil.NopCloser(bytes.NewReader(sh.piped))
+	}
+	in := newInputs(sh.names)
+	in.bom = *bom
+	in.headers = *headers
+	return in
+}
+
+// everyone's patience remembers different things, so everyone gets their own.
+// ramping over everything means counting everything first, and since files
+// are opened again for everyone, they're counted again for everyone too.
+func (sh *show) options() ([]slow.Option, error) {
+	length := 0
+	if needsLength() {
+		content := sh.open()
+		data, err := ioutil.ReadAll(content)
+		content.Close()
+		if err != nil {
+			return nil, err
+		}
+		length = utf8.RuneCount(data)
+	}
+	_, opts, err := optionsFromFlags(length)
+	return opts, err
+}
+
+// play the whole show for one client, until it's over or ctx is done. flush
+// is how to get everything written to dst to the client, or nil if dst knows
+// how to flush itself.
+func (sh *show) play(ctx context.Context, dst io.Writer, flush func() error) error {
+	opts, err := sh.options()
+	if err != nil {
+		return err
+	}
+
+	if sh.limit > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sh.limit)
+		defer cancel()
+	}
+	if sh.stall > 0 {
+		stall := time.NewTimer(sh.stall)
+		defer stall.Stop()
+		select {
+		case <-stall.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	var d *dripper
+	if sh.drip > 0 {
+		d = &dripper{w: dst, flush: flush, size: sh.drip}
+		dst, flush = d, func() error { return nil }
+	}
+	if flush != nil {
+		opts = append(opts, slow.WithFlush(flush))
+	}
+	w := slow.New(dst, append(opts, slow.WithContext(ctx))...)
+
+	content := sh.open()
+	defer content.Close()
+	if _, err := io.Copy(w, content); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	// a few bytes short of a whole drip still have to go out eventually,
+	// unless it's already time to hang up
+	if d != nil {
+		return d.Close()
+	}
+	return nil
+}
+
+// holds writes until there's enough of them to send size bytes at once.
+// whatever's left over goes out on Close.
+type dripper struct {
+	w     io.Writer
+	flush func() error
+	size  int
+	buf   []byte
+}
+
+func (d *dripper) Write(p []byte) (int, error) {
+	d.buf = append(d.buf, p...)
+	for len(d.buf) >= d.size {
+		if err := d.send(d.buf[:d.size]); err != nil {
+			return len(p), err
+		}
+		d.buf = d.buf[d.size:]
+	}
+	return len(p), nil
+}
+
+func (d *dripper) Close() error {
+	if len(d.buf) == 0 {
+		return nil
+	}
+	err := d.send(d.buf)
+	d.buf = nil
+	return err
+}
+
+func (d *dripper) send(p []byte) error {
+	if _, err := d.w.Write(p); err != nil {
+		return err
+	}
+	if d.flush != nil {
+		return d.flush()
+	}
+	return nil
+}
+
+// a single file is whatever it looks like, and everything else is text
+func (sh *show) contentType() string {
+	if len(sh.names) == 1 {
+		if t := mime.TypeByExtension(filepath.Ext(sh.names[0])); t != "" {
+			return t
+		}
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// every request gets the whole show as a chunked response, flushed after
+// every token. anyone asking for a websocket or server-sent events gets those
+// instead.
+func serveHTTP(ctx context.Context, l net.Listener, sh *show) error {
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if isWebSocket(r) {
+				serveWebSocket(rw, r, sh)
+				return
+			}
+			if wantsEvents(r) {
+				serveEvents(rw, r, sh)
+				return
+			}
+
+			rw.Header().Set("Content-Type", sh.contentType())
+			rw.Header().Set("X-Content-Type-Options", "nosniff")
+
+			flush := func() error { return nil }
+			if f, ok := rw.(http.Flusher); ok {
+				flush = func() error { f.Flush(); return nil }
+			}
+			// slow.Handler would be patient with whatever a handler writes,
+			// but dripping, stalling, and hanging up all happen between the
+			// Writer and the client, so the show plays itself. running out of
+			// time or the client hanging up isn't worth mentioning.
+			err := sh.play(r.Context(), rw, flush)
+			if err != nil && r.Context().Err() == nil && !errors.Is(err, context.DeadlineExceeded) {
+				fmt.Fprintf(os.Stderr, "error playing to %s: %s\n", r.RemoteAddr, err)
+			}
+		}),
+		// everyone still watching gets cut off when it's time to go
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		done <- srv.Shutdown(context.Background())
+	}()
+
+	if err := srv.Serve(l); err != http.ErrServerClosed {
+		return err
+	}
+	return <-done
+}
+
+// every connection gets the whole show, and then gets hung up on
+func serveTCP(ctx context.Context, l net.Listener, sh *show) error {
+	return each(ctx, l, func(ctx context.Context, conn net.Conn) {
+		sh.play(ctx, conn, nil)
+	})
+}
+
+// accept every connection to l until ctx is done, and handle each one on its
+// own. each closes connections once they've been handled, and doesn't return
+// until all of them have been.
+func each(ctx context.Context, l net.Listener, handle func(ctx context.Context, conn net.Conn)) error {
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer conn.Close()
+			handle(ctx, conn)
+		}()
+	}
+}
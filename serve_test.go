@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// a writer that remembers every Write on its own
+type sends []string
+
+func (s *sends) Write(p []byte) (int, error) {
+	*s = append(*s, string(p))
+	return len(p), nil
+}
+
+func TestDripper(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int
+		writes []string
+		want   []string
+	}{
+		{"nothing", 4, nil, nil},
+		{"exactly", 2, []string{"a", "b", "c", "d"}, []string{"ab", "cd"}},
+		// whatever's short of a whole drip waits for Close
+		{"left over", 2, []string{"a", "b", "c"}, []string{"ab", "c"}},
+		{"big write", 2, []string{"abcde"}, []string{"ab", "cd", "e"}},
+		{"bigger than a drip", 3, []string{"ab", "cdef", "g"}, []string{"abc", "def", "g"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got sends
+			flushes := 0
+			d := &dripper{w: &got, flush: func() error { flushes++; return nil }, size: tt.size}
+			for _, p := range tt.writes {
+				if n, err := d.Write([]byte(p)); err != nil || n != len(p) {
+					t.Fatalf("Write(%q) = %d, %v", p, n, err)
+				}
+			}
+			if err := d.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("sent %q, want %q", got, tt.want)
+			}
+			// every drip is flushed on its own
+			if flushes != len(tt.want) {
+				t.Errorf("flushed %d times, want %d", flushes, len(tt.want))
+			}
+		})
+	}
+}
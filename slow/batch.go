@@ -0,0 +1,133 @@
+package slow
+
+import (
+	"time"
+)
+
+// A Plan is a schedule for writing a batch of bytes all at once. Writing a
+// Plan writes every Chunk in order, waiting after each one.
+type Plan struct {
+	Chunks []Chunk
+	// Total is how long it'll take to write every Chunk, put together.
+	Total time.Duration
+}
+
+// A Chunk is one write in a Plan.
+type Chunk struct {
+	Data  []byte
+	Delay time.Duration
+}
+
+// Plan decides how to write p without writing any of it. p is treated as if
+// nothing else will be written after it, so every token in p is planned, even
+// incomplete ones.
+//
+// Consecutive tokens are gathered into the same Chunk until waiting for them
+// adds up to at least quantum. A quantum of zero or less puts every token in
+// a Chunk of its own.
+//
+// Plan asks the Writer's Patience how long to wait for every rune right away,
+// and anything watching with WithOnRune hears about them as they're planned.
+func (w *Writer) Plan(p []byte, quantum time.Duration) (Plan, error) {
+	var plan Plan
+	var chunk Chunk
+
+	var m match
+	for off := 0; off < len(p); {
+		advance, token, fast, err := w.split(p, off, true, &m)
+		if err != nil {
+			return plan, err
+		}
+		if advance == 0 {
+			break
+		}
+		off += advance
+		if token == nil {
+			continue
+		}
+
+		chunk.Data = append(chunk.Data, token...)
+		if fast {
+			w.pass(token)
+		} else {
+			chunk.Delay += w.delay(token, p[off:])
+		}
+		if chunk.Delay >= quantum {
+			plan.add(chunk)
+			chunk = Chunk{}
+		}
+	}
+	if len(chunk.Data) > 0 {
+		plan.add(chunk)
+	}
+	return plan, nil
+}
+
+// Stretch returns a copy of p that takes exactly total to write, by scaling
+// every Chunk's delay by the same amount. If p doesn't wait at all, the time
+// is split evenly between Chunks.
+func (p Plan) Stretch(total time.Duration) Plan {
+	if len(p.Chunks) == 0 {
+		return p
+	}
+
+	stretched := Plan{Chunks: make([]Chunk, 0, len(p.Chunks))}
+	for _, c := range p.Chunks {
+		if p.Total > 0 {
+			c.Delay = time.Duration(float64(c.Delay) * float64(total) / float64(p.Total))
+		} else {
+			c.Delay = total / time.Duration(len(p.Chunks))
+		}
+		stretched.add(c)
+	}
+
+	// rounding is never exact, so make the last wait make up the difference
+	last := &stretched.Chunks[len(stretched.Chunks)-1]
+	last.Delay += total - stretched.Total
+	stretched.Total = total
+	return stretched
+}
+
+func (p *Plan) add(c Chunk) {
+	p.Chunks = append(p.Chunks, c)
+	p.Total += c.Delay
+}
+
+// WriteBatch writes a Plan, one Chunk at a time. Anything the Writer was
+// holding from an earlier Write is written first.
+//
+// WriteBatch returns the number of bytes written from the Plan, and fails the
+// same way Write does.
+func (w *Writer) WriteBatch(plan Plan) (int, error) {
+	if _, err := w.writeTokens(true); err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, c := range plan.Chunks {
+		delay := c.Delay
+		if w.skipped() {
+			delay = 0
+		}
+
+		err := w.emit(c.Data, delay)
+		if _, unwritten := err.(*WriteError); !unwritten {
+			n += len(c.Data)
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// WriteStringPaced plans s and then writes the Plan, returning it along with
+// any error writing it. See Plan and WriteBatch.
+func (w *Writer) WriteStringPaced(s string, quantum time.Duration) (Plan, error) {
+	plan, err := w.Plan([]byte(s), quantum)
+	if err != nil {
+		return plan, err
+	}
+	_, err = w.WriteBatch(plan)
+	return plan, err
+}
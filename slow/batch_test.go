@@ -0,0 +1,121 @@
+package slow_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+	"github.com/blinsay/aslap/slow/slowtest"
+)
+
+func checkPlan(t *testing.T, got, want slow.Plan) {
+	t.Helper()
+
+	if len(got.Chunks) != len(want.Chunks) || got.Total != want.Total {
+		t.Fatalf("got plan %s, want %s", describe(got), describe(want))
+	}
+	for i := range got.Chunks {
+		if !bytes.Equal(got.Chunks[i].Data, want.Chunks[i].Data) || got.Chunks[i].Delay != want.Chunks[i].Delay {
+			t.Fatalf("got plan %s, want %s", describe(got), describe(want))
+		}
+	}
+}
+
+func describe(p slow.Plan) string {
+	var buf bytes.Buffer
+	for _, c := range p.Chunks {
+		buf.WriteString(string(c.Data) + " " + c.Delay.String() + ", ")
+	}
+	buf.WriteString("total " + p.Total.String())
+	return buf.String()
+}
+
+func TestPlan(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name    string
+		input   string
+		quantum time.Duration
+		want    slow.Plan
+	}{
+		{"nothing", "", 0, slow.Plan{}},
+		{"every token", "abc", 0, slow.Plan{
+			Chunks: []slow.Chunk{{[]byte("a"), 97 * ms}, {[]byte("b"), 98 * ms}, {[]byte("c"), 99 * ms}},
+			Total:  294 * ms,
+		}},
+		{"quantum", "abc", 150 * ms, slow.Plan{
+			Chunks: []slow.Chunk{{[]byte("ab"), 195 * ms}, {[]byte("c"), 99 * ms}},
+			Total:  294 * ms,
+		}},
+		{"everything at once", "abc", time.Hour, slow.Plan{
+			Chunks: []slow.Chunk{{[]byte("abc"), 294 * ms}},
+			Total:  294 * ms,
+		}},
+		// nothing comes after a plan, so incomplete runes are planned too
+		{"incomplete", "a\xe4", 0, slow.Plan{
+			Chunks: []slow.Chunk{{[]byte("a"), 97 * ms}, {[]byte("�"), 0xfffd * ms}},
+			Total:  (97 + 0xfffd) * ms,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := slowtest.NewClock(time.Unix(0, 0))
+			var out bytes.Buffer
+			w := slow.New(&out, slow.WithPatience(codePoints), slow.WithClock(clock))
+
+			plan, err := w.Plan([]byte(tt.input), tt.quantum)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkPlan(t, plan, tt.want)
+			if out.Len() > 0 || len(clock.Sleeps()) > 0 {
+				t.Fatalf("planning wrote %q and slept %v", out.String(), clock.Sleeps())
+			}
+
+			// writing the plan waits exactly as planned
+			if _, err := w.WriteBatch(plan); err != nil {
+				t.Fatal(err)
+			}
+			var want []time.Duration
+			for _, c := range tt.want.Chunks {
+				want = append(want, c.Delay)
+			}
+			checkSleeps(t, clock.Sleeps(), want)
+		})
+	}
+}
+
+func TestStretch(t *testing.T) {
+	ms := time.Millisecond
+	chunks := func(delays ...time.Duration) slow.Plan {
+		var p slow.Plan
+		for _, d := range delays {
+			p.Chunks = append(p.Chunks, slow.Chunk{Data: []byte("x"), Delay: d})
+			p.Total += d
+		}
+		return p
+	}
+
+	tests := []struct {
+		name  string
+		plan  slow.Plan
+		total time.Duration
+		want  slow.Plan
+	}{
+		{"nothing", slow.Plan{}, time.Second, slow.Plan{}},
+		{"longer", chunks(100*ms, 300*ms), time.Second, chunks(250*ms, 750*ms)},
+		{"shorter", chunks(100*ms, 300*ms), 100 * ms, chunks(25*ms, 75*ms)},
+		{"no waiting", chunks(0, 0, 0, 0), time.Second, chunks(250*ms, 250*ms, 250*ms, 250*ms)},
+		// whatever doesn't divide evenly is made up at the end
+		{"rounding", chunks(0, 0, 0), 100, chunks(33, 33, 34)},
+		{"no time", chunks(100*ms, 300*ms), 0, chunks(0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkPlan(t, tt.plan.Stretch(tt.total), tt.want)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package slow
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// WriteCast writes p as an asciinema cast, version 2, for a terminal that's
+// width columns wide and height rows tall. Every Chunk is an output event at
+// the time it'd be written if p started at the beginning of the recording.
+//
+// A cast is replayed exactly as written, so anything that isn't already
+// written the way a terminal expects it, like newlines without carriage
+// returns, won't look right.
+func (p Plan) WriteCast(w io.Writer, width, height int) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	header := struct {
+		Version   int               `json:"version"`
+		Width     int               `json:"width"`
+		Height    int               `json:"height"`
+		Timestamp int64             `json:"timestamp"`
+		Env       map[string]string `json:"env"`
+	}{2, width, height, time.Now().Unix(), map[string]string{"TERM": "xterm-256color"}}
+	if err := enc.Encode(header); err != nil {
+		return err
+	}
+
+	var at, last time.Duration
+	for _, c := range p.Chunks {
+		if len(c.Data) > 0 {
+			if err := enc.Encode([]interface{}{seconds(at), "o", string(c.Data)}); err != nil {
+				return err
+			}
+			last = at
+		}
+		at += c.Delay
+	}
+
+	// a pause at the very end only lasts if something happens after it
+	if at > last {
+		return enc.Encode([]interface{}{seconds(at), "o", ""})
+	}
+	return nil
+}
+
+// to the microsecond, which is as close as anyone can tell, and without any
+// floating point noise on the end
+func seconds(d time.Duration) float64 {
+	return float64(d/time.Microsecond) / 1e6
+}
@@ -0,0 +1,99 @@
+package slow
+
+import (
+	"math/rand"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WordPieces splits input into pieces of words, roughly the way a language
+// model's tokenizer does. Spaces stick to the start of the word after them,
+// long words are broken into pieces of three to six letters, numbers are
+// broken into groups of up to three digits, and everything else is a piece of
+// its own.
+var WordPieces Tokenizer = TokenizerFunc(scanWordPieces)
+
+func scanWordPieces(data []byte, atEOF bool) (int, []byte, error) {
+	if len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	start := 0
+	if data[0] == ' ' {
+		start = 1
+	}
+	if start == len(data) && !atEOF {
+		return 0, nil, nil
+	}
+
+	if !utf8.FullRune(data[start:]) && !atEOF {
+		return 0, nil, nil
+	}
+	r, size := utf8.DecodeRune(data[start:])
+	if start == len(data) || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+		// a space that isn't in front of anything is a piece of its own
+		if start == 1 {
+			return 1, data[:1], nil
+		}
+		return size, data[:size], nil
+	}
+
+	// letters come in pieces of three to six, picked by the first letter so the
+	// same word always splits the same way. digits come in threes.
+	same, limit := unicode.IsLetter, 3+int(r)%4
+	if unicode.IsDigit(r) {
+		same, limit = unicode.IsDigit, 3
+	}
+
+	end, n := start, 0
+	for end < len(data) && n < limit {
+		if !utf8.FullRune(data[end:]) && !atEOF {
+			return 0, nil, nil
+		}
+		r, size := utf8.DecodeRune(data[end:])
+		if !same(r) {
+			return end, data[:end], nil
+		}
+		end += size
+		n++
+	}
+	if end == len(data) && n < limit && !atEOF {
+		return 0, nil, nil
+	}
+	return end, data[:end], nil
+}
+
+// Chat returns Patience that streams like a chat model, at about tps tokens a
+// second. Most tokens come in quick bursts, and every so often the stream
+// stalls for a while before the next burst.
+//
+// Chat waits the same no matter which rune it's asked about, and it's meant to
+// be asked once per token. Use it with WordPieces and without length scaling.
+// Randomness comes from rnd, exactly like Jitter. It panics if tps isn't
+// positive.
+func Chat(tps float64, rnd *rand.Rand) Patience {
+	if tps <= 0 {
+		panic("rate must be positive")
+	}
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	const (
+		stallChance = 0.15
+		stallMin    = 3
+		stallMax    = 8
+	)
+
+	// stalls take up some of the average, and bursts make up the rest
+	mean := float64(time.Second) / tps
+	quick := mean * (1 - stallChance*(stallMin+stallMax)/2) / (1 - stallChance)
+
+	return PatienceFunc(func(rune) time.Duration {
+		if rnd.Float64() < stallChance {
+			return time.Duration(mean * (stallMin + rnd.Float64()*(stallMax-stallMin)))
+		}
+		return time.Duration(quick * rnd.ExpFloat64())
+	})
+}
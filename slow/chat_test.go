@@ -0,0 +1,68 @@
+package slow_test
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestWordPieces(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		// h picks pieces of three letters, and w picks pieces of six
+		{"words", "hello world", []string{"hel", "lo", " world"}},
+		{"digits", "12345", []string{"123", "45"}},
+		{"punctuation", "hi!", []string{"hi", "!"}},
+		{"spaces", "a  b", []string{"a", " ", " b"}},
+		{"trailing space", "a ", []string{"a", " "}},
+		{"not ascii", "über", []string{"übe", "r"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tokens(t, slow.WordPieces, tt.input)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("split %q into %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChat(t *testing.T) {
+	tests := []struct {
+		name string
+		tps  float64
+	}{
+		{"slow", 2},
+		{"fast", 50},
+	}
+
+	const samples = 20000
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := slow.Chat(tt.tps, rand.New(rand.NewSource(1)))
+
+			var total time.Duration
+			for i := 0; i < samples; i++ {
+				d := p.Delay('a')
+				if d < 0 {
+					t.Fatalf("Delay = %v", d)
+				}
+				total += d
+			}
+
+			// bursts and stalls average out to the rate
+			mean := float64(total) / samples
+			if want := float64(time.Second) / tt.tps; math.Abs(mean-want) > 0.05*want {
+				t.Errorf("delays averaged %v, want about %v", time.Duration(mean), time.Duration(want))
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package slow
+
+import (
+	"time"
+	"unicode"
+)
+
+// A Class is a broad kind of character.
+type Class int
+
+// Every rune is in exactly one Class.
+const (
+	// ClassLetter is every letter, in any script.
+	ClassLetter Class = iota
+	// ClassDigit is every decimal digit.
+	ClassDigit
+	// ClassPunctuation is every punctuation mark, along with the symbols on an
+	// ordinary keyboard like $, +, and ~.
+	ClassPunctuation
+	// ClassSpace is every kind of whitespace, including newlines and tabs.
+	ClassSpace
+	// ClassControl is every control character that isn't whitespace.
+	ClassControl
+	// ClassOther is everything else: emoji, combining marks, and so on.
+	ClassOther
+)
+
+// ClassOf returns the Class r is in.
+func ClassOf(r rune) Class {
+	switch {
+	case unicode.IsSpace(r):
+		return ClassSpace
+	case unicode.IsControl(r):
+		return ClassControl
+	case unicode.IsLetter(r):
+		return ClassLetter
+	case unicode.IsDigit(r):
+		return ClassDigit
+	case unicode.IsPunct(r), r < unicode.MaxASCII && unicode.IsSymbol(r):
+		return ClassPunctuation
+	default:
+		return ClassOther
+	}
+}
+
+// ByClass returns Patience that waits as long as classes says to for every
+// Class in it, and as long as p does for everything else.
+func ByClass(p Patience, classes map[Class]time.Duration) Patience {
+	return &byClass{p: p, classes: classes}
+}
+
+type byClass struct {
+	p       Patience
+	classes map[Class]time.Duration
+}
+
+func (c *byClass) Delay(r rune) time.Duration {
+	return c.DelayAt(r, Position{Prev: -1})
+}
+
+func (c *byClass) DelayAt(r rune, pos Position) time.Duration {
+	if d, ok := c.classes[ClassOf(r)]; ok {
+		return d
+	}
+	return delayAt(c.p, r, pos)
+}
+
+func (c *byClass) Reset() {
+	reset(c.p)
+}
@@ -0,0 +1,48 @@
+package slow_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestClassOf(t *testing.T) {
+	tests := []struct {
+		r    rune
+		want slow.Class
+	}{
+		{'a', slow.ClassLetter},
+		{'Ж', slow.ClassLetter},
+		{'世', slow.ClassLetter},
+		{'7', slow.ClassDigit},
+		{'٣', slow.ClassDigit},
+		{'.', slow.ClassPunctuation},
+		{'$', slow.ClassPunctuation},
+		{'+', slow.ClassPunctuation},
+		{'«', slow.ClassPunctuation},
+		{' ', slow.ClassSpace},
+		{'\n', slow.ClassSpace},
+		{'\t', slow.ClassSpace},
+		{'\x1b', slow.ClassControl},
+		{'🐢', slow.ClassOther},
+		{'\u0301', slow.ClassOther},
+		// only symbols on an ordinary keyboard count as punctuation
+		{'€', slow.ClassOther},
+	}
+
+	for _, tt := range tests {
+		if got := slow.ClassOf(tt.r); got != tt.want {
+			t.Errorf("ClassOf(%q) = %v, want %v", tt.r, got, tt.want)
+		}
+	}
+}
+
+func TestByClass(t *testing.T) {
+	ms := time.Millisecond
+	p := slow.ByClass(always(time.Second), map[slow.Class]time.Duration{
+		slow.ClassLetter: 10 * ms,
+		slow.ClassSpace:  0,
+	})
+	checkSleeps(t, waits(t, p, "a b1"), []time.Duration{10 * ms, 0, 10 * ms, time.Second})
+}
@@ -0,0 +1,40 @@
+package slow
+
+import (
+	"context"
+	"time"
+)
+
+// A Clock tells time and waits around. Writers and Readers do all of their
+// waiting with a Clock, so that anything that would rather not actually wait
+// (tests, mostly) doesn't have to.
+type Clock interface {
+	Now() time.Time
+
+	// Sleep waits for d or until ctx is done, whichever comes first. If ctx is
+	// done, Sleep returns ctx.Err().
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// the system clock
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,192 @@
+package slow
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/rivo/uniseg"
+)
+
+// Chain returns Patience that waits as long as all of ps put together.
+func Chain(ps ...Patience) Patience {
+	return chain(ps)
+}
+
+type chain []Patience
+
+func (c chain) Delay(r rune) time.Duration {
+	return c.DelayAt(r, Position{Prev: -1})
+}
+
+func (c chain) DelayAt(r rune, pos Position) time.Duration {
+	var total time.Duration
+	for _, p := range c {
+		total += delayAt(p, r, pos)
+	}
+	return total
+}
+
+func (c chain) Reset() {
+	for _, p := range c {
+		reset(p)
+	}
+}
+
+// Jitter returns Patience that waits as long as p, give or take a uniformly
+// random amount of up to amount. Jitter never waits less than zero.
+//
+// Randomness comes from rnd, which shouldn't be shared by anything that might
+// be running at the same time. If rnd is nil, Jitter uses a randomly seeded
+// source of its own.
+func Jitter(p Patience, amount time.Duration, rnd *rand.Rand) Patience {
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return &jitter{p: p, amount: amount, rnd: rnd}
+}
+
+type jitter struct {
+	p        Patience
+	amount   time.Duration
+	fraction float64
+	rnd      *rand.Rand
+}
+
+func (j *jitter) Delay(r rune) time.Duration {
+	return j.DelayAt(r, Position{Prev: -1})
+}
+
+func (j *jitter) DelayAt(r rune, pos Position) time.Duration {
+	d := delayAt(j.p, r, pos)
+
+	amount := j.amount
+	if j.fraction > 0 {
+		amount = time.Duration(float64(d) * j.fraction)
+	}
+	if amount > 0 {
+		d += time.Duration(j.rnd.Int63n(int64(2*amount)+1)) - amount
+	}
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+func (j *jitter) Reset() {
+	reset(j.p)
+}
+
+// JitterFraction is like Jitter, but the random amount is up to fraction of
+// however long p waits, instead of a fixed amount. A fraction of 0.1 waits as
+// long as p, give or take ten percent.
+func JitterFraction(p Patience, fraction float64, rnd *rand.Rand) Patience {
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return &jitter{p: p, fraction: fraction, rnd: rnd}
+}
+
+// Clamp returns Patience that waits as long as p, but never less than min or
+// more than max.
+func Clamp(p Patience, min, max time.Duration) Patience {
+	return &clamped{p: p, min: min, max: max}
+}
+
+type clamped struct {
+	p        Patience
+	min, max time.Duration
+}
+
+func (c *clamped) Delay(r rune) time.Duration {
+	return c.DelayAt(r, Position{Prev: -1})
+}
+
+func (c *clamped) DelayAt(r rune, pos Position) time.Duration {
+	d := delayAt(c.p, r, pos)
+	if d < c.min {
+		return c.min
+	}
+	if d > c.max {
+		return c.max
+	}
+	return d
+}
+
+func (c *clamped) Reset() {
+	reset(c.p)
+}
+
+// Scale returns Patience that waits factor times as long as p. A factor less
+// than one is less patient, and more than one is more.
+func Scale(p Patience, factor float64) Patience {
+	return &scaled{p: p, factor: factor}
+}
+
+type scaled struct {
+	p      Patience
+	factor float64
+}
+
+func (s *scaled) Delay(r rune) time.Duration {
+	return s.DelayAt(r, Position{Prev: -1})
+}
+
+func (s *scaled) DelayAt(r rune, pos Position) time.Duration {
+	return time.Duration(float64(delayAt(s.p, r, pos)) * s.factor)
+}
+
+func (s *scaled) Reset() {
+	reset(s.p)
+}
+
+// Encoded returns Patience that waits as long as p says for every byte it
+// takes to encode a rune in UTF-8. ASCII waits as long as p does, and CJK and
+// emoji wait three or four times as long, like it costs to send them.
+func Encoded(p Patience) Patience {
+	return &encoded{p: p}
+}
+
+type encoded struct {
+	p Patience
+}
+
+func (e *encoded) Delay(r rune) time.Duration {
+	return e.DelayAt(r, Position{Prev: -1})
+}
+
+func (e *encoded) DelayAt(r rune, pos Position) time.Duration {
+	return delayAt(e.p, r, pos) * time.Duration(runeLen(r))
+}
+
+func (e *encoded) Reset() {
+	reset(e.p)
+}
+
+// Wide returns Patience that waits factor times as long as p for characters
+// that take up two columns on a terminal, like CJK ideographs and most emoji,
+// and exactly as long as p for everything else. A factor of 2 treats every
+// wide character like two narrow ones, so text fills the screen evenly.
+func Wide(p Patience, factor float64) Patience {
+	return &wide{p: p, factor: factor}
+}
+
+type wide struct {
+	p      Patience
+	factor float64
+}
+
+func (w *wide) Delay(r rune) time.Duration {
+	return w.DelayAt(r, Position{Prev: -1})
+}
+
+func (w *wide) DelayAt(r rune, pos Position) time.Duration {
+	d := delayAt(w.p, r, pos)
+	if uniseg.StringWidth(string(r)) > 1 {
+		d = time.Duration(float64(d) * w.factor)
+	}
+	return d
+}
+
+func (w *wide) Reset() {
+	reset(w.p)
+}
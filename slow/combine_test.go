@@ -0,0 +1,86 @@
+package slow_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+// waits exactly d for everything
+func always(d time.Duration) slow.Patience {
+	return slow.PatienceFunc(func(rune) time.Duration { return d })
+}
+
+func TestCombinators(t *testing.T) {
+	tests := []struct {
+		name string
+		p    slow.Patience
+		r    rune
+		want time.Duration
+	}{
+		{"chain nothing", slow.Chain(), 'a', 0},
+		{"chain", slow.Chain(always(time.Second), codePoints), 'a', 1097 * time.Millisecond},
+		{"clamp under", slow.Clamp(codePoints, 100*time.Millisecond, 200*time.Millisecond), 'a', 100 * time.Millisecond},
+		{"clamp within", slow.Clamp(codePoints, 50*time.Millisecond, 200*time.Millisecond), 'a', 97 * time.Millisecond},
+		{"clamp over", slow.Clamp(codePoints, 0, 50*time.Millisecond), 'a', 50 * time.Millisecond},
+		{"scale up", slow.Scale(always(time.Second), 2), 'a', 2 * time.Second},
+		{"scale down", slow.Scale(always(time.Second), 0.25), 'a', 250 * time.Millisecond},
+		{"no jitter", slow.Jitter(always(time.Second), 0, nil), 'a', time.Second},
+		{"encoded ascii", slow.Encoded(always(10 * time.Millisecond)), 'a', 10 * time.Millisecond},
+		{"encoded", slow.Encoded(always(10 * time.Millisecond)), 'é', 20 * time.Millisecond},
+		{"encoded cjk", slow.Encoded(always(10 * time.Millisecond)), '世', 30 * time.Millisecond},
+		{"encoded emoji", slow.Encoded(always(10 * time.Millisecond)), '🐢', 40 * time.Millisecond},
+		{"wide", slow.Wide(always(time.Second), 2), '世', 2 * time.Second},
+		{"wide emoji", slow.Wide(always(time.Second), 2), '🐢', 2 * time.Second},
+		{"not wide", slow.Wide(always(time.Second), 2), 'a', time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.Delay(tt.r); got != tt.want {
+				t.Errorf("Delay(%q) = %v, want %v", tt.r, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJitter(t *testing.T) {
+	tests := []struct {
+		name     string
+		p        func(rnd *rand.Rand) slow.Patience
+		min, max time.Duration
+	}{
+		{"amount", func(rnd *rand.Rand) slow.Patience { return slow.Jitter(always(time.Second), 100*time.Millisecond, rnd) }, 900 * time.Millisecond, 1100 * time.Millisecond},
+		{"never negative", func(rnd *rand.Rand) slow.Patience { return slow.Jitter(always(0), time.Second, rnd) }, 0, time.Second},
+		{"fraction", func(rnd *rand.Rand) slow.Patience { return slow.JitterFraction(always(time.Second), 0.5, rnd) }, 500 * time.Millisecond, 1500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.p(rand.New(rand.NewSource(1)))
+			again := tt.p(rand.New(rand.NewSource(1)))
+
+			varied := false
+			first := p.Delay('a')
+			for i := 0; i < 100; i++ {
+				d := p.Delay('a')
+				if d < tt.min || d > tt.max {
+					t.Fatalf("Delay = %v, want between %v and %v", d, tt.min, tt.max)
+				}
+				if d != first {
+					varied = true
+				}
+			}
+			if !varied {
+				t.Errorf("Delay was always %v", first)
+			}
+
+			// the same seed jitters the same way
+			if d := again.Delay('a'); d != first {
+				t.Errorf("the same seed waited %v and %v", first, d)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package slow
+
+import (
+	"context"
+	"net"
+	"sync"
+)
+
+// A Conn is a net.Conn that patiently writes everything, the same way a
+// Writer does. Reads pass straight through to the underlying connection.
+type Conn struct {
+	net.Conn
+
+	mu     sync.Mutex
+	w      *Writer
+	cancel context.CancelFunc
+}
+
+// NewConn wraps c so that writes to it are as slow as possible. Writes are
+// configured by opts, exactly like New.
+func NewConn(c net.Conn, opts ...Option) *Conn {
+	w := New(c, opts...)
+
+	// closing the connection stops waiting, and so does anything that would
+	// have stopped the Writer anyway
+	ctx, cancel := context.WithCancel(w.ctx)
+	w.setContext(ctx)
+
+	return &Conn{
+		Conn:   c,
+		w:      w,
+		cancel: cancel,
+	}
+}
+
+// Write patiently writes p to the connection. Concurrent calls to Write are
+// written one after the other.
+func (c *Conn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.w.Write(p)
+}
+
+// Skip stops being patient, exactly like Writer.Skip.
+func (c *Conn) Skip() {
+	c.w.Skip()
+}
+
+// SetMultiplier changes how fast writes go, exactly like Writer.SetMultiplier.
+func (c *Conn) SetMultiplier(m float64) {
+	c.w.SetMultiplier(m)
+}
+
+// Stats returns everything written to the connection so far, without waiting
+// for a Write that's in progress to finish.
+func (c *Conn) Stats() Stats {
+	return c.w.Stats()
+}
+
+// Close closes the connection. Any Write still waiting stops waiting, and
+// anything still held is thrown away.
+func (c *Conn) Close() error {
+	c.cancel()
+	return c.Conn.Close()
+}
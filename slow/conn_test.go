@@ -0,0 +1,95 @@
+package slow_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+	"github.com/blinsay/aslap/slow/slowtest"
+)
+
+func TestConn(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name   string
+		chunks []string
+		want   []time.Duration
+	}{
+		{"nothing", nil, nil},
+		{"one write", []string{"ab"}, []time.Duration{97 * ms, 98 * ms}},
+		{"split rune", []string{"\xc3", "\xa9"}, []time.Duration{233 * ms}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := net.Pipe()
+			defer client.Close()
+
+			clock := slowtest.NewClock(time.Unix(0, 0))
+			c := slow.NewConn(server, slow.WithClock(clock), slow.WithPatience(codePoints))
+
+			go func() {
+				for _, chunk := range tt.chunks {
+					io.WriteString(c, chunk)
+				}
+				c.Close()
+			}()
+
+			// reads pass right through
+			got, err := io.ReadAll(client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := strings.Join(tt.chunks, ""); string(got) != want {
+				t.Errorf("read %q, want %q", got, want)
+			}
+			checkSleeps(t, clock.Sleeps(), tt.want)
+		})
+	}
+}
+
+func TestConnClose(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+	}{
+		// closing stops waiting
+		{"close", context.Background()},
+		// and so does anything that would have stopped the Writer anyway
+		{"context", canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := net.Pipe()
+			defer client.Close()
+			go io.Copy(io.Discard, client)
+
+			clock := stuck{slowtest.NewClock(time.Unix(0, 0)), make(chan struct{})}
+			c := slow.NewConn(server, slow.WithClock(clock), slow.WithContext(tt.ctx))
+
+			done := make(chan error)
+			go func() {
+				_, err := io.WriteString(c, "ab")
+				done <- err
+			}()
+
+			if tt.ctx.Err() == nil {
+				<-clock.waiting
+				c.Close()
+			}
+			if err := <-done; !errors.Is(err, context.Canceled) {
+				t.Errorf("got error %v, want %v", err, context.Canceled)
+			}
+			c.Close()
+		})
+	}
+}
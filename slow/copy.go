@@ -0,0 +1,48 @@
+package slow
+
+import (
+	"context"
+	"io"
+)
+
+// Copy copies runes from src to dst, being patient about writing every one,
+// and returns Stats about everything that was written. If p is a Resetter,
+// it's reset before anything is copied.
+func Copy(dst io.Writer, src io.Reader, p Patience) (Stats, error) {
+	return CopyContext(context.Background(), dst, src, p)
+}
+
+// CopyContext is like Copy, but stops waiting as soon as ctx is done and
+// returns ctx.Err(). Any other error is a ReadError, a WriteError, or a
+// FlushError.
+//
+// A read from src that's already blocked can't be interrupted. CopyContext
+// notices ctx is done once it returns.
+func CopyContext(ctx context.Context, dst io.Writer, src io.Reader, p Patience) (Stats, error) {
+	reset(p)
+
+	w := NewWriter(dst, p)
+	w.setContext(ctx)
+
+	_, err := io.Copy(w, contextReader{ctx, src})
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	return w.Stats(), err
+}
+
+// a reader that won't read once its context is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
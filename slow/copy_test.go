@@ -0,0 +1,62 @@
+package slow_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestCopy(t *testing.T) {
+	// Copy always waits on the system clock, so every wait is over in a
+	// matter of nanoseconds
+	nanos := slow.PatienceFunc(func(r rune) time.Duration {
+		return time.Duration(r)
+	})
+
+	tests := []struct {
+		name  string
+		input string
+		want  slow.Stats
+	}{
+		{"nothing", "", slow.Stats{}},
+		{"ascii", "ab", slow.Stats{Runes: 2, Bytes: 2, Tokens: 2, Slept: 97 + 98, MinDelay: 97, MaxDelay: 98}},
+		{"multibyte", "é", slow.Stats{Runes: 1, Bytes: 2, Tokens: 1, Slept: 233, MinDelay: 233, MaxDelay: 233}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			stats, err := slow.Copy(&out, strings.NewReader(tt.input), nanos)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.input {
+				t.Errorf("copied %q, want %q", out.String(), tt.input)
+			}
+
+			// how long it all took is up to the system clock
+			stats.Wall = 0
+			if stats != tt.want {
+				t.Errorf("got stats %+v, want %+v", stats, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var out bytes.Buffer
+	_, err := slow.CopyContext(ctx, &out, strings.NewReader("abc"), slow.BePatient(0, time.Hour, 0))
+	if err != context.Canceled {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+	if out.Len() > 0 {
+		t.Errorf("copied %q after being canceled", out.String())
+	}
+}
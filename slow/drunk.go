@@ -0,0 +1,64 @@
+package slow
+
+import (
+	"math"
+	"math/rand"
+	"time"
+	"unicode"
+)
+
+// Drunk returns Patience that starts out exactly as patient as p and falls
+// apart over the first n runes. Delays get more and more erratic, until
+// they're anywhere from a fifth of p's to nearly twice as long, and it gets
+// more and more likely to lose its train of thought between words.
+//
+// Pair Drunk with Typos that Ramp over the same n runes to make mistakes fall
+// apart too. Randomness comes from rnd, exactly like Jitter.
+func Drunk(p Patience, n int, rnd *rand.Rand) Patience {
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return &drunk{p: p, n: n, rnd: rnd}
+}
+
+const (
+	// how erratic delays get, as a fraction of the delay, at the very worst
+	drunkJitter = 0.8
+	// the chance of stopping between words at the very worst, and how long
+	// stopping takes, in delays
+	drunkStopChance = 0.3
+	drunkStopMin    = 2
+	drunkStopMax    = 10
+)
+
+type drunk struct {
+	p   Patience
+	n   int
+	rnd *rand.Rand
+}
+
+func (d *drunk) Delay(r rune) time.Duration {
+	return d.DelayAt(r, Position{Prev: -1})
+}
+
+func (d *drunk) DelayAt(r rune, pos Position) time.Duration {
+	delay := float64(delayAt(d.p, r, pos))
+
+	level := 1.0
+	if d.n > 0 {
+		level = math.Min(float64(pos.Rune)/float64(d.n), 1)
+	}
+
+	base := delay
+	delay += base * level * drunkJitter * (2*d.rnd.Float64() - 1)
+
+	endOfWord := unicode.IsSpace(r) && pos.Prev >= 0 && !unicode.IsSpace(pos.Prev)
+	if endOfWord && d.rnd.Float64() < level*drunkStopChance {
+		delay += base * (drunkStopMin + d.rnd.Float64()*(drunkStopMax-drunkStopMin))
+	}
+	return time.Duration(delay)
+}
+
+func (d *drunk) Reset() {
+	reset(d.p)
+}
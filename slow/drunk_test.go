@@ -0,0 +1,57 @@
+package slow_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestDrunk(t *testing.T) {
+	ms := time.Millisecond
+	base := 100 * ms
+
+	t.Run("sober", func(t *testing.T) {
+		// the very first rune hasn't had anything to drink yet
+		_, sleeps := play(t, []string{"a"}, slow.WithPatience(slow.Drunk(always(base), 100, rand.New(rand.NewSource(1)))))
+		checkSleeps(t, sleeps, []time.Duration{base})
+	})
+
+	t.Run("barely drunk", func(t *testing.T) {
+		// a few runes into a thousand is hardly anything
+		for seed := int64(0); seed < 20; seed++ {
+			_, sleeps := play(t, []string{"abc"}, slow.WithPatience(slow.Drunk(always(base), 1000, rand.New(rand.NewSource(seed)))))
+			if sleeps[0] != base {
+				t.Fatalf("seed %d: waited %v for the first rune, want %v", seed, sleeps[0], base)
+			}
+			for _, d := range sleeps[1:] {
+				if d < 99*ms || d > 101*ms {
+					t.Fatalf("seed %d: waited %v barely drunk, want about %v", seed, d, base)
+				}
+			}
+		}
+	})
+
+	t.Run("drunk", func(t *testing.T) {
+		input := strings.Repeat("abcd ", 200)
+		_, sleeps := play(t, []string{input}, slow.WithPatience(slow.Drunk(always(base), 0, rand.New(rand.NewSource(1)))))
+
+		stops := 0
+		for i, r := range input {
+			d := sleeps[i]
+			if r == ' ' && d > 2*base {
+				stops++
+				continue
+			}
+			if d < base/5 || d > 2*base {
+				t.Fatalf("waited %v after %q, want between %v and %v", d, r, base/5, 2*base)
+			}
+		}
+		// about 30% of the 200 spaces
+		if stops < 40 || stops > 80 {
+			t.Errorf("stopped %d times between 200 words, want about 60", stops)
+		}
+	})
+}
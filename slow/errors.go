@@ -0,0 +1,54 @@
+package slow
+
+import (
+	"errors"
+	"os"
+)
+
+// A ReadError is returned when reading whatever's being slowed down fails.
+type ReadError struct {
+	Err error
+}
+
+func (e *ReadError) Error() string {
+	return "read error: " + e.Err.Error()
+}
+
+func (e *ReadError) Unwrap() error {
+	return e.Err
+}
+
+// A WriteError is returned when writing to whatever's being slowed down fails.
+type WriteError struct {
+	Err error
+}
+
+func (e *WriteError) Error() string {
+	return "write error: " + e.Err.Error()
+}
+
+func (e *WriteError) Unwrap() error {
+	return e.Err
+}
+
+// Timeout returns true if the write failed because it took too long.
+func (e *WriteError) Timeout() bool {
+	if errors.Is(e.Err, os.ErrDeadlineExceeded) {
+		return true
+	}
+	var t interface{ Timeout() bool }
+	return errors.As(e.Err, &t) && t.Timeout()
+}
+
+// A FlushError is returned when flushing after a write fails.
+type FlushError struct {
+	Err error
+}
+
+func (e *FlushError) Error() string {
+	return "flush error: " + e.Err.Error()
+}
+
+func (e *FlushError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,122 @@
+package slow_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+	"github.com/blinsay/aslap/slow/slowtest"
+)
+
+var boom = errors.New("boom")
+
+// a writer that takes n writes and then fails every one after that
+type failAfter struct {
+	n       int
+	written bytes.Buffer
+}
+
+func (f *failAfter) Write(p []byte) (int, error) {
+	if f.n == 0 {
+		return 0, boom
+	}
+	f.n--
+	return f.written.Write(p)
+}
+
+func TestErrors(t *testing.T) {
+	clock := slow.WithClock(slowtest.NewClock(time.Unix(0, 0)))
+	newReader := func(r io.Reader) *slow.Reader {
+		sr := slow.NewReader(r, codePoints)
+		sr.SetClock(slowtest.NewClock(time.Unix(0, 0)))
+		return sr
+	}
+
+	tests := []struct {
+		name string
+		err  func() error
+		// one of *slow.ReadError, *slow.WriteError, or *slow.FlushError
+		as   interface{}
+		text string
+	}{
+		{"write", func() error {
+			_, err := io.WriteString(slow.New(&failAfter{}, clock), "a")
+			return err
+		}, new(*slow.WriteError), "write error: boom"},
+		{"flush", func() error {
+			_, err := io.WriteString(slow.New(io.Discard, clock, slow.WithFlush(func() error { return boom })), "a")
+			return err
+		}, new(*slow.FlushError), "flush error: boom"},
+		{"read from", func() error {
+			_, err := slow.New(io.Discard, clock).ReadFrom(iotest.ErrReader(boom))
+			return err
+		}, new(*slow.ReadError), "read error: boom"},
+		{"read", func() error {
+			_, err := newReader(iotest.ErrReader(boom)).Read(make([]byte, 8))
+			return err
+		}, new(*slow.ReadError), "read error: boom"},
+		{"write to", func() error {
+			_, err := newReader(strings.NewReader("a")).WriteTo(&failAfter{})
+			return err
+		}, new(*slow.WriteError), "write error: boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.err()
+			if !errors.As(err, tt.as) {
+				t.Fatalf("got error %v, want a %T", err, tt.as)
+			}
+			if !errors.Is(err, boom) {
+				t.Errorf("%v doesn't wrap %v", err, boom)
+			}
+			if err.Error() != tt.text {
+				t.Errorf("got error %q, want %q", err.Error(), tt.text)
+			}
+		})
+	}
+}
+
+func TestPartialWrites(t *testing.T) {
+	tests := []struct {
+		name string
+		// how many tokens get written before writing fails
+		ok     int
+		writes []string
+		// how much of the last write Write says it wrote
+		want int
+	}{
+		{"nothing", 0, []string{"abc"}, 0},
+		{"some", 2, []string{"abc"}, 2},
+		// the held byte was already counted by the first Write
+		{"held rune fails", 1, []string{"a\xe4", "\xb8\x96b"}, 0},
+		{"after a held rune", 2, []string{"a\xe4", "\xb8\x96b"}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &failAfter{n: tt.ok}
+			w := slow.New(f, slow.WithClock(slowtest.NewClock(time.Unix(0, 0))))
+
+			var n int
+			var err error
+			for _, p := range tt.writes {
+				if n, err = io.WriteString(w, p); err != nil {
+					break
+				}
+			}
+			var werr *slow.WriteError
+			if !errors.As(err, &werr) {
+				t.Fatalf("got error %v, want a WriteError", err)
+			}
+			if n != tt.want {
+				t.Errorf("Write says it wrote %d bytes, want %d", n, tt.want)
+			}
+		})
+	}
+}
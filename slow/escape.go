@@ -0,0 +1,48 @@
+package slow
+
+// the escape character that starts every ANSI escape sequence
+const esc = 0x1b
+
+// how long the escape sequence at the start of p is, and whether it's all
+// there. p has to start with an escape.
+//
+// control sequences (CSI) go until a final byte, and strings like operating
+// system commands (OSC) go until a string terminator or a bell. everything
+// else is an escape, any number of intermediate bytes, and a final byte.
+func escapeLen(p []byte) (int, bool) {
+	if len(p) < 2 {
+		return len(p), false
+	}
+
+	switch p[1] {
+	case '[':
+		for i := 2; i < len(p); i++ {
+			if 0x40 <= p[i] && p[i] <= 0x7e {
+				return i + 1, true
+			}
+			if p[i] < 0x20 || p[i] > 0x3f {
+				// not a control sequence after all
+				return i, true
+			}
+		}
+	case ']', 'P', 'X', '^', '_':
+		for i := 2; i < len(p); i++ {
+			if p[i] == 0x07 && p[1] == ']' {
+				return i + 1, true
+			}
+			if p[i] == esc && i+1 < len(p) && p[i+1] == '\\' {
+				return i + 2, true
+			}
+		}
+	default:
+		for i := 1; i < len(p); i++ {
+			if 0x30 <= p[i] && p[i] <= 0x7e {
+				return i + 1, true
+			}
+			if p[i] < 0x20 || p[i] > 0x2f {
+				return i, true
+			}
+		}
+	}
+	return len(p), false
+}
@@ -0,0 +1,44 @@
+package slow_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestInstantEscapes(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name   string
+		chunks []string
+		want   []time.Duration
+	}{
+		{"csi", []string{"a\x1b[31mb"}, []time.Duration{97 * ms, 0, 98 * ms}},
+		{"split csi", []string{"a\x1b[3", "1mb"}, []time.Duration{97 * ms, 0, 98 * ms}},
+		{"osc with a bell", []string{"\x1b]0;hi\x07x"}, []time.Duration{0, 120 * ms}},
+		{"osc with a terminator", []string{"\x1b]0;hi\x1b\\x"}, []time.Duration{0, 120 * ms}},
+		{"two byte escape", []string{"\x1bcx"}, []time.Duration{0, 120 * ms}},
+		// not a control sequence after all, so whatever comes after is text
+		{"broken csi", []string{"\x1b[\nx"}, []time.Duration{0, 10 * ms, 120 * ms}},
+		// an escape that never finishes still gets written eventually
+		{"unfinished", []string{"a\x1b[3"}, []time.Duration{97 * ms, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, sleeps := play(t, tt.chunks, slow.WithPatience(codePoints), slow.WithInstantEscapes())
+			if want := strings.Join(tt.chunks, ""); got != want {
+				t.Errorf("wrote %q, want %q", got, want)
+			}
+			checkSleeps(t, sleeps, tt.want)
+		})
+	}
+}
+
+func TestEscapesWithoutInstantEscapes(t *testing.T) {
+	ms := time.Millisecond
+	_, sleeps := play(t, []string{"\x1b[m"}, slow.WithPatience(codePoints))
+	checkSleeps(t, sleeps, []time.Duration{27 * ms, 91 * ms, 109 * ms})
+}
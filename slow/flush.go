@@ -0,0 +1,64 @@
+package slow
+
+import (
+	"io"
+	"sync"
+)
+
+var (
+	flushersMu sync.RWMutex
+	flushers   []func(io.Writer) (func() error, bool)
+)
+
+// RegisterFlusher teaches every Writer a new way to flush. After every token,
+// Writers that weren't given WithFlush flush whatever they're writing to.
+// Writers can flush or sync anything that looks like an http.Flusher, a
+// bufio.Writer, or an os.File on their own.
+//
+// f is asked about every new Writer's underlying writer. If f knows how to
+// flush it, f returns a func that does the flushing and true. Flushers are
+// asked in the order they were registered, before any of the built-in ones.
+// Any error a flush returns is returned from Write as a FlushError.
+func RegisterFlusher(f func(w io.Writer) (flush func() error, ok bool)) {
+	flushersMu.Lock()
+	defer flushersMu.Unlock()
+
+	flushers = append(flushers, f)
+}
+
+// returns a func that flushes this writer. if the writer is unflushable,
+// returns a noop.
+//
+// this is here just in case you'd like to aslap an io.Writer that isn't
+// an os.File
+//
+// syncing isn't always possible (try syncing a terminal) so errors from Sync
+// are ignored.
+func makeFlush(w io.Writer) func() error {
+	flushersMu.RLock()
+	defer flushersMu.RUnlock()
+
+	for _, f := range flushers {
+		if flush, ok := f(w); ok {
+			return flush
+		}
+	}
+
+	// http.Flusher
+	type flusher interface{ Flush() }
+	// bufio.Writer
+	type safeFlusher interface{ Flush() error }
+	// os.File
+	type syncer interface{ Sync() error }
+
+	switch t := w.(type) {
+	case flusher:
+		return func() error { t.Flush(); return nil }
+	case safeFlusher:
+		return t.Flush
+	case syncer:
+		return func() error { t.Sync(); return nil }
+	default:
+		return func() error { return nil }
+	}
+}
@@ -0,0 +1,88 @@
+package slow_test
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+	"github.com/blinsay/aslap/slow/slowtest"
+)
+
+// a writer nothing knows how to flush, until it's registered
+type tank struct {
+	bytes.Buffer
+	flushes int
+	fail    error
+}
+
+func init() {
+	slow.RegisterFlusher(func(w io.Writer) (func() error, bool) {
+		t, ok := w.(*tank)
+		if !ok {
+			return nil, false
+		}
+		return func() error {
+			t.flushes++
+			return t.fail
+		}, true
+	})
+}
+
+func TestRegisterFlusher(t *testing.T) {
+	tests := []struct {
+		name    string
+		fail    error
+		opts    []slow.Option
+		flushes int
+	}{
+		{"registered", nil, nil, 3},
+		// anything that asks for its own flush gets it instead
+		{"with flush", nil, []slow.Option{slow.WithFlush(func() error { return nil })}, 0},
+		{"failed", boom, nil, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tk := &tank{fail: tt.fail}
+			opts := append([]slow.Option{slow.WithClock(slowtest.NewClock(time.Unix(0, 0)))}, tt.opts...)
+			w := slow.New(tk, opts...)
+
+			_, err := io.WriteString(w, "abc")
+			if tt.fail != nil {
+				var ferr *slow.FlushError
+				if !errors.As(err, &ferr) || !errors.Is(err, tt.fail) {
+					t.Errorf("got error %v, want a FlushError wrapping %v", err, tt.fail)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if tk.flushes != tt.flushes {
+				t.Errorf("flushed %d times, want %d", tk.flushes, tt.flushes)
+			}
+		})
+	}
+}
+
+func TestBuiltInFlushers(t *testing.T) {
+	var out bytes.Buffer
+	bw := bufio.NewWriter(&out)
+	w := slow.New(bw, slow.WithClock(slowtest.NewClock(time.Unix(0, 0))))
+
+	// a registered flusher doesn't get in the way of the ones that were
+	// already there
+	for _, token := range []string{"a", "b"} {
+		if _, err := io.WriteString(w, token); err != nil {
+			t.Fatal(err)
+		}
+		if bw.Buffered() != 0 {
+			t.Fatalf("%d bytes still buffered after writing %q", bw.Buffered(), token)
+		}
+	}
+	if out.String() != "ab" {
+		t.Errorf("wrote %q, want %q", out.String(), "ab")
+	}
+}
@@ -0,0 +1,74 @@
+package slow
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"time"
+	"unicode"
+)
+
+// English is how often every character shows up in ordinary English text, as
+// a percentage of all characters.
+var English = map[rune]float64{
+	' ': 18.3, 'e': 10.2, 't': 7.5, 'a': 6.5, 'o': 6.2, 'i': 5.7, 'n': 5.7,
+	's': 5.3, 'h': 5.0, 'r': 5.0, 'd': 3.5, 'l': 3.3, 'u': 2.3, 'c': 2.2,
+	'm': 2.0, 'f': 1.8, 'w': 1.7, 'g': 1.6, 'y': 1.6, 'p': 1.5, 'b': 1.2,
+	'v': 0.8, 'k': 0.6, '.': 0.65, ',': 0.6, '\n': 0.5, '\'': 0.2, '"': 0.2,
+	'x': 0.15, '-': 0.15, 'j': 0.1, 'q': 0.08, 'z': 0.06, '?': 0.05,
+	'!': 0.04, ';': 0.03, ':': 0.03,
+}
+
+// Frequency returns Patience that's quick with common characters and slow with
+// rare ones. The most common character in freqs waits fast, and the rarest
+// waits slow, with everything else in between on a log scale. Anything that's
+// not in freqs at all waits slow. Letters are counted the same in either case.
+func Frequency(fast, slow time.Duration, freqs map[rune]float64) Patience {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, f := range freqs {
+		if f <= 0 {
+			continue
+		}
+		lo, hi = math.Min(lo, f), math.Max(hi, f)
+	}
+
+	return PatienceFunc(func(r rune) time.Duration {
+		f, ok := freqs[r]
+		if !ok {
+			f, ok = freqs[unicode.ToLower(r)]
+		}
+		if !ok || f <= 0 {
+			return slow
+		}
+
+		score := 1.0
+		if hi > lo {
+			score = math.Log(f/lo) / math.Log(hi/lo)
+		}
+		return slow - time.Duration(float64(slow-fast)*score)
+	})
+}
+
+// ParseFrequencies reads a table of character frequencies for Frequency from
+// JSON. Keys are single characters or code points like "U+000A", and values
+// are how often they show up. Values only matter relative to each other, so
+// percentages and raw counts both work.
+//
+//	{"e": 12.7, "t": 9.1, " ": 18, "U+000A": 0.5}
+func ParseFrequencies(r io.Reader) (map[rune]float64, error) {
+	var raw map[string]float64
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	freqs := make(map[rune]float64, len(raw))
+	for key, f := range raw {
+		lo, hi, err := parseRuneRange(key)
+		if err != nil || lo != hi {
+			return nil, fmt.Errorf("invalid character: %q", key)
+		}
+		freqs[lo] = f
+	}
+	return freqs, nil
+}
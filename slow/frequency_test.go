@@ -0,0 +1,77 @@
+package slow_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestFrequency(t *testing.T) {
+	ms := time.Millisecond
+	freqs := map[rune]float64{'a': 100, 'b': 10, 'c': 1, 'd': 0}
+
+	tests := []struct {
+		name  string
+		freqs map[rune]float64
+		r     rune
+		want  time.Duration
+	}{
+		{"most common", freqs, 'a', 0},
+		{"rarest", freqs, 'c', 200 * ms},
+		// halfway between on a log scale
+		{"in between", freqs, 'b', 100 * ms},
+		{"uppercase", freqs, 'A', 0},
+		{"never", freqs, 'd', 200 * ms},
+		{"unknown", freqs, 'z', 200 * ms},
+		{"only one", map[rune]float64{'a': 5}, 'a', 0},
+		{"english", slow.English, ' ', 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slow.Frequency(0, 200*ms, tt.freqs).Delay(tt.r); !near(got, tt.want) {
+				t.Errorf("Delay(%q) = %v, want %v", tt.r, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFrequencies(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[rune]float64
+		err   string
+	}{
+		{"characters", `{"e": 12.7, " ": 18}`, map[rune]float64{'e': 12.7, ' ': 18}, ""},
+		{"code points", `{"U+000A": 0.5}`, map[rune]float64{'\n': 0.5}, ""},
+		{"not json", `e`, nil, "invalid character"},
+		{"range", `{"a-z": 1}`, nil, `invalid character: "a-z"`},
+		{"too long", `{"th": 1}`, nil, `invalid character: "th"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := slow.ParseFrequencies(strings.NewReader(tt.input))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("got error %v, want one about %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for r, f := range tt.want {
+				if got[r] != f {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
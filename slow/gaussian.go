@@ -0,0 +1,25 @@
+package slow
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Gaussian returns Patience that waits a normally distributed amount of time
+// with the given mean and standard deviation. Delays that would be less than
+// zero are zero.
+//
+// Randomness comes from rnd, exactly like Jitter.
+func Gaussian(mean, stddev time.Duration, rnd *rand.Rand) Patience {
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	return PatienceFunc(func(rune) time.Duration {
+		d := mean + time.Duration(rnd.NormFloat64()*float64(stddev))
+		if d < 0 {
+			return 0
+		}
+		return d
+	})
+}
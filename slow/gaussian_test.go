@@ -0,0 +1,45 @@
+package slow_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestGaussian(t *testing.T) {
+	tests := []struct {
+		name         string
+		mean, stddev time.Duration
+		// what the delays average out to, give or take 5%
+		want time.Duration
+	}{
+		{"no deviation", time.Second, 0, time.Second},
+		{"deviation", time.Second, 100 * time.Millisecond, time.Second},
+		// half of everything would be negative, and it's all zero instead,
+		// which means the rest averages out to about 0.4σ
+		{"never negative", 0, time.Second, 399 * time.Millisecond},
+	}
+
+	const samples = 10000
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := slow.Gaussian(tt.mean, tt.stddev, rand.New(rand.NewSource(1)))
+
+			var total time.Duration
+			for i := 0; i < samples; i++ {
+				d := p.Delay('a')
+				if d < 0 {
+					t.Fatalf("Delay = %v", d)
+				}
+				total += d
+			}
+			mean := total / samples
+			if math.Abs(float64(mean-tt.want)) > 0.05*float64(tt.want) {
+				t.Errorf("delays averaged %v, want about %v", mean, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package slow
+
+import (
+	"context"
+	"net/http"
+)
+
+// Handler returns an http.Handler that serves everything next does, as slowly
+// as possible. Response bodies are written the same way a Writer configured by
+// opts would write them, and flushed after every token.
+//
+// opts is called once for every request, so every response gets Patience of
+// its own. Patience that remembers what it's seen can't be shared between
+// requests that are being served at the same time.
+//
+// Handler stops waiting when a request's context is done, or when the context
+// from WithContext is.
+func Handler(next http.Handler, opts func() []Option) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		w := New(rw, opts()...)
+
+		// the request can only stop the Writer sooner than it would have
+		// stopped anyway
+		ctx, cancel := context.WithCancel(w.ctx)
+		defer cancel()
+		stop := context.AfterFunc(r.Context(), cancel)
+		defer stop()
+		w.setContext(ctx)
+
+		srw := &responseWriter{ResponseWriter: rw, w: w}
+		next.ServeHTTP(srw, r)
+		w.Close()
+	})
+}
+
+// an http.ResponseWriter that writes patiently
+type responseWriter struct {
+	http.ResponseWriter
+	w *Writer
+}
+
+func (rw *responseWriter) Write(p []byte) (int, error) {
+	return rw.w.Write(p)
+}
+
+// everything is always flushed anyway, but anything that asks should still get
+// a flush.
+func (rw *responseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// for http.ResponseController
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,98 @@
+package slow_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+	"github.com/blinsay/aslap/slow/slowtest"
+)
+
+func TestHandler(t *testing.T) {
+	hello := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		io.WriteString(rw, "hello")
+	})
+
+	// jitter remembers where it is in its random source, so every request
+	// needs its own
+	var made atomic.Int32
+	opts := func() []slow.Option {
+		made.Add(1)
+		return []slow.Option{
+			slow.WithClock(slowtest.NewClock(time.Unix(0, 0))),
+			slow.WithPatience(slow.Jitter(always(time.Second), 100*time.Millisecond, rand.New(rand.NewSource(1)))),
+		}
+	}
+
+	srv := httptest.NewServer(slow.Handler(hello, opts))
+	defer srv.Close()
+
+	const requests = 8
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := http.Get(srv.URL)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if string(body) != "hello" {
+				t.Errorf("got %q, want %q", body, "hello")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := made.Load(); n != requests {
+		t.Errorf("made options %d times for %d requests", n, requests)
+	}
+}
+
+func TestHandlerContext(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{"waiting", context.Background(), nil},
+		// the request is still going, but everything else is over
+		{"configured context done", canceled, context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			hello := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				_, err = io.WriteString(rw, "hello")
+			})
+			opts := func() []slow.Option {
+				return []slow.Option{slow.WithClock(slowtest.NewClock(time.Unix(0, 0))), slow.WithContext(tt.ctx)}
+			}
+
+			rec := httptest.NewRecorder()
+			slow.Handler(hello, opts).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("got error %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
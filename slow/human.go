@@ -0,0 +1,95 @@
+package slow
+
+import (
+	"math"
+	"math/rand"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Human returns Patience that types like a person, taking about base for every
+// character. People type in quick bursts, hesitate before long words, and get
+// slower the longer they've been typing.
+//
+// Randomness comes from rnd, exactly like Jitter. A Human has to remember how
+// tired it is, so it can't be shared between Writers. Reset it to start fresh.
+func Human(base time.Duration, rnd *rand.Rand) Patience {
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return &human{base: base, rnd: rnd}
+}
+
+const (
+	// the chance of a burst starting after any rune, and how long bursts last
+	burstChance = 0.08
+	burstMin    = 3
+	burstMax    = 10
+	burstSpeed  = 0.4
+
+	// words at least this long are worth thinking about first, sometimes
+	longWord        = 8
+	hesitateChance  = 0.5
+	hesitateMinimum = 3
+	hesitateMaximum = 8
+
+	// after this many runes, every rune takes twice as long. that's as tired
+	// as anyone gets.
+	fatigueRunes = 5000
+	maxFatigue   = 2
+)
+
+type human struct {
+	base time.Duration
+	rnd  *rand.Rand
+
+	typed int
+	burst int
+}
+
+func (h *human) Delay(r rune) time.Duration {
+	return h.DelayAt(r, Position{Prev: -1})
+}
+
+func (h *human) DelayAt(r rune, pos Position) time.Duration {
+	h.typed++
+	fatigue := math.Min(1+float64(h.typed)/fatigueRunes, maxFatigue)
+
+	// nobody types with perfect rhythm
+	d := float64(h.base) * fatigue * math.Exp(h.rnd.NormFloat64()*0.25)
+
+	if h.burst > 0 {
+		h.burst--
+		d *= burstSpeed
+	} else if h.rnd.Float64() < burstChance {
+		h.burst = burstMin + h.rnd.Intn(burstMax-burstMin+1)
+	}
+
+	if unicode.IsSpace(r) && nextWordLen(pos.Ahead) >= longWord && h.rnd.Float64() < hesitateChance {
+		d += float64(h.base) * (hesitateMinimum + h.rnd.Float64()*(hesitateMaximum-hesitateMinimum))
+	}
+	return time.Duration(d)
+}
+
+func (h *human) Reset() {
+	h.typed, h.burst = 0, 0
+}
+
+// the length in runes of the word at the start of b, after any whitespace
+func nextWordLen(b []byte) int {
+	n := 0
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		b = b[size:]
+
+		if unicode.IsSpace(r) {
+			if n > 0 {
+				break
+			}
+			continue
+		}
+		n++
+	}
+	return n
+}
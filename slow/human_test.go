@@ -0,0 +1,60 @@
+package slow_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestHuman(t *testing.T) {
+	const base = 100 * time.Millisecond
+	tests := []struct {
+		name  string
+		r     rune
+		ahead string
+		// how many runes have already been typed
+		typed int
+		// what the delay averages out to, as a multiple of base
+		min, max float64
+	}{
+		{"fresh", 'a', "", 0, 0.9, 1.2},
+		// tired hands take twice as long, except that by then there have
+		// been plenty of quick bursts
+		{"tired", 'a', "", 5000, 1.45, 1.85},
+		{"before a short word", ' ', "to", 0, 0.9, 1.2},
+		// half the time there's a pause of three to eight characters
+		{"before a long word", ' ', "extraordinary", 0, 3, 4.5},
+	}
+
+	const samples = 200
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := slow.Human(base, rand.New(rand.NewSource(1))).(slow.PositionalPatience)
+
+			var total time.Duration
+			for i := 0; i < samples; i++ {
+				p.(slow.Resetter).Reset()
+				for j := 0; j < tt.typed; j++ {
+					p.Delay('a')
+				}
+				total += p.DelayAt(tt.r, slow.Position{Prev: 'x', Ahead: []byte(tt.ahead)})
+			}
+			mean := float64(total) / samples / float64(base)
+			if mean < tt.min || mean > tt.max {
+				t.Errorf("delays averaged %.2f times base, want between %v and %v", mean, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestHumanSeeded(t *testing.T) {
+	a := slow.Human(100*time.Millisecond, rand.New(rand.NewSource(7)))
+	b := slow.Human(100*time.Millisecond, rand.New(rand.NewSource(7)))
+	for i, r := range "the same seed types the same way" {
+		if da, db := a.Delay(r), b.Delay(r); da != db {
+			t.Fatalf("rune %d waited %v and %v", i, da, db)
+		}
+	}
+}
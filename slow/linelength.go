@@ -0,0 +1,55 @@
+package slow
+
+import (
+	"math"
+	"time"
+	"unicode/utf8"
+)
+
+// LineLength returns Patience that waits longer or shorter depending on how
+// long the line is. Every rune on a line waits as long as p says, scaled by the
+// line's length in runes divided by reference, all to the power of exponent.
+//
+// An exponent of 1 dribbles out long lines more slowly and short ones more
+// quickly, exactly in proportion to their length. An exponent of -1 does the
+// opposite, so every line takes about the same time. A line exactly reference
+// runes long always waits exactly as long as p.
+//
+// LineLength can only see as much of a line as has already been written. Use
+// it with WithLineBuffering to make sure that's the whole line.
+func LineLength(p Patience, reference int, exponent float64) Patience {
+	return &lineLength{p: p, reference: reference, exponent: exponent, factor: 1}
+}
+
+type lineLength struct {
+	p         Patience
+	reference int
+	exponent  float64
+
+	factor float64
+}
+
+func (l *lineLength) Delay(r rune) time.Duration {
+	return l.DelayAt(r, Position{Prev: -1})
+}
+
+func (l *lineLength) DelayAt(r rune, pos Position) time.Duration {
+	// work the factor out once, from how long the line turns out to be, and
+	// keep it for every rune until the next one starts
+	if pos.Column == 0 {
+		length := 1
+		if r != '\n' {
+			length += utf8.RuneCount(firstLine(pos.Ahead))
+		}
+		l.factor = 1
+		if l.reference > 0 {
+			l.factor = math.Pow(float64(length)/float64(l.reference), l.exponent)
+		}
+	}
+	return time.Duration(float64(delayAt(l.p, r, pos)) * l.factor)
+}
+
+func (l *lineLength) Reset() {
+	l.factor = 1
+	reset(l.p)
+}
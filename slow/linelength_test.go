@@ -0,0 +1,42 @@
+package slow_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestLineLength(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name      string
+		reference int
+		exponent  float64
+		chunks    []string
+		buffered  bool
+		want      []time.Duration
+	}{
+		{"reference", 2, 1, []string{"ab\n"}, false, []time.Duration{100 * ms, 100 * ms, 100 * ms}},
+		{"longer", 2, 1, []string{"abcd\n"}, false, []time.Duration{200 * ms, 200 * ms, 200 * ms, 200 * ms, 200 * ms}},
+		{"shorter", 2, 1, []string{"a\n"}, false, []time.Duration{50 * ms, 50 * ms}},
+		// every line takes about as long as every other
+		{"inverse", 2, -1, []string{"abcd\nab\n"}, false, []time.Duration{50 * ms, 50 * ms, 50 * ms, 50 * ms, 50 * ms, 100 * ms, 100 * ms, 100 * ms}},
+		{"blank line", 2, 1, []string{"\n"}, false, []time.Duration{50 * ms}},
+		{"no reference", 0, 1, []string{"abcd\n"}, false, []time.Duration{100 * ms, 100 * ms, 100 * ms, 100 * ms, 100 * ms}},
+		// only what's been written so far counts
+		{"split line", 2, 1, []string{"a", "bcd\n"}, false, []time.Duration{50 * ms, 50 * ms, 50 * ms, 50 * ms, 50 * ms}},
+		{"split line buffered", 2, 1, []string{"a", "bcd\n"}, true, []time.Duration{200 * ms, 200 * ms, 200 * ms, 200 * ms, 200 * ms}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []slow.Option{slow.WithPatience(slow.LineLength(always(100*ms), tt.reference, tt.exponent))}
+			if tt.buffered {
+				opts = append(opts, slow.WithLineBuffering())
+			}
+			_, sleeps := play(t, tt.chunks, opts...)
+			checkSleeps(t, sleeps, tt.want)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package slow
+
+import (
+	"bytes"
+	"time"
+	"unicode/utf8"
+)
+
+// MuscleMemory returns Patience that remembers the last window bytes it's
+// seen, and goes faster through anything it's seen before. A run of at least
+// min runes that's already in memory waits factor times as long as p says for
+// every rune in it, so a factor of 0.2 types boilerplate five times as fast.
+//
+// MuscleMemory can only recognize what's already been written, so it's best
+// when there's plenty of Position.Ahead. A MuscleMemory remembers everything
+// it sees, so it can't be shared between Writers.
+func MuscleMemory(p Patience, window, min int, factor float64) Patience {
+	return &memory{p: p, window: window, min: min, factor: factor}
+}
+
+// the longest repeat worth looking for all at once, in bytes
+const maxRepeat = 256
+
+type memory struct {
+	p      Patience
+	window int
+	min    int
+	factor float64
+
+	seen   []byte
+	repeat int
+}
+
+func (m *memory) Delay(r rune) time.Duration {
+	return m.DelayAt(r, Position{Prev: -1})
+}
+
+func (m *memory) DelayAt(r rune, pos Position) time.Duration {
+	if m.repeat == 0 {
+		m.repeat = m.recall(append(encodeRune(r), pos.Ahead[:clamp(len(pos.Ahead), 0, maxRepeat)]...))
+	}
+	m.remember(r)
+
+	d := delayAt(m.p, r, pos)
+	if m.repeat > 0 {
+		m.repeat--
+		return time.Duration(float64(d) * m.factor)
+	}
+	return d
+}
+
+// how many runes at the start of p are part of something already seen, or
+// zero if it's less than min
+func (m *memory) recall(p []byte) int {
+	n, runes := 0, 0
+	for runes < m.min && n < len(p) {
+		_, size := utf8.DecodeRune(p[n:])
+		n, runes = n+size, runes+1
+	}
+	if runes < m.min || bytes.Index(m.seen, p[:n]) < 0 {
+		return 0
+	}
+
+	for n < len(p) {
+		_, size := utf8.DecodeRune(p[n:])
+		if bytes.Index(m.seen, p[:n+size]) < 0 {
+			break
+		}
+		n, runes = n+size, runes+1
+	}
+	return runes
+}
+
+func (m *memory) remember(r rune) {
+	m.seen = append(m.seen, encodeRune(r)...)
+	if over := len(m.seen) - m.window; over > 0 {
+		m.seen = m.seen[:copy(m.seen, m.seen[over:])]
+	}
+}
+
+func (m *memory) Reset() {
+	m.seen, m.repeat = nil, 0
+	reset(m.p)
+}
@@ -0,0 +1,37 @@
+package slow_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestMuscleMemory(t *testing.T) {
+	ms := time.Millisecond
+	normal, fast := 100*ms, 50*ms
+	tests := []struct {
+		name   string
+		window int
+		min    int
+		chunks []string
+		want   []time.Duration
+	}{
+		{"nothing seen", 100, 3, []string{"abcdef"}, []time.Duration{normal, normal, normal, normal, normal, normal}},
+		{"repeat", 100, 3, []string{"abcabc"}, []time.Duration{normal, normal, normal, fast, fast, fast}},
+		{"multibyte repeat", 100, 2, []string{"é世é世"}, []time.Duration{normal, normal, fast, fast}},
+		// a repeat stops being fast as soon as it stops being a repeat
+		{"partial repeat", 100, 3, []string{"abcdabcx"}, []time.Duration{normal, normal, normal, normal, fast, fast, fast, normal}},
+		{"too short", 100, 3, []string{"abxab"}, []time.Duration{normal, normal, normal, normal, normal}},
+		{"forgotten", 2, 3, []string{"abcabc"}, []time.Duration{normal, normal, normal, normal, normal, normal}},
+		// a repeat that hasn't all been written yet can't be recognized
+		{"split writes", 100, 3, []string{"abca", "bc"}, []time.Duration{normal, normal, normal, normal, normal, normal}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, sleeps := play(t, tt.chunks, slow.WithPatience(slow.MuscleMemory(always(normal), tt.window, tt.min, 0.5)))
+			checkSleeps(t, sleeps, tt.want)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package slow
+
+import (
+	"io"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// International Morse Code, for everything that has it
+var morseCodes = map[rune]string{
+	'a': ".-", 'b': "-...", 'c': "-.-.", 'd': "-..", 'e': ".", 'f': "..-.",
+	'g': "--.", 'h': "....", 'i': "..", 'j': ".---", 'k': "-.-", 'l': ".-..",
+	'm': "--", 'n': "-.", 'o': "---", 'p': ".--.", 'q': "--.-", 'r': ".-.",
+	's': "...", 't': "-", 'u': "..-", 'v': "...-", 'w': ".--", 'x': "-..-",
+	'y': "-.--", 'z': "--..",
+
+	'0': "-----", '1': ".----", '2': "..---", '3': "...--", '4': "....-",
+	'5': ".....", '6': "-....", '7': "--...", '8': "---..", '9': "----.",
+
+	'.': ".-.-.-", ',': "--..--", '?': "..--..", '\'': ".----.", '!': "-.-.--",
+	'/': "-..-.", '(': "-.--.", ')': "-.--.-", '&': ".-...", ':': "---...",
+	';': "-.-.-.", '=': "-...-", '+': ".-.-.", '-': "-....-", '_': "..--.-",
+	'"': ".-..-.", '$': "...-..-", '@': ".--.-.",
+}
+
+// MorseCode returns the International Morse Code for r, as dots and dashes.
+// Letters are the same in either case.
+func MorseCode(r rune) (string, bool) {
+	code, ok := morseCodes[unicode.ToLower(r)]
+	return code, ok
+}
+
+// Morse returns Patience that keeps International Morse timing at wpm words per
+// minute, by the PARIS standard. Every rune takes as long as it would to key
+// its dits and dahs with a dit's silence between them, followed by the three
+// dit gap between characters. Whitespace finishes the seven dit gap between
+// words. Anything without a code just gets the gap between characters.
+//
+// It panics if wpm isn't positive.
+func Morse(wpm float64) Patience {
+	if wpm <= 0 {
+		panic("rate must be positive")
+	}
+	dit := time.Duration(float64(1200*time.Millisecond) / wpm)
+
+	return PatienceFunc(func(r rune) time.Duration {
+		if unicode.IsSpace(r) {
+			return 4 * dit
+		}
+
+		code, ok := MorseCode(r)
+		if !ok {
+			return 3 * dit
+		}
+
+		units := 0
+		for _, element := range code {
+			if element == '-' {
+				units += 3
+			} else {
+				units++
+			}
+		}
+		units += len(code) - 1
+		return time.Duration(units+3) * dit
+	})
+}
+
+// NewMorseWriter returns a Writer that writes the Morse Code for everything
+// written to it to w, with a space after every character and a slash between
+// words. Line breaks and anything without a code pass through as-is.
+//
+// If alongside is true, every character is written first with its code in
+// brackets after it, instead of the code replacing it.
+//
+// Put a NewMorseWriter underneath a Writer that's keeping Morse time to see
+// (or hear) every character keyed.
+func NewMorseWriter(w io.Writer, alongside bool) io.Writer {
+	return &morseWriter{w: w, flush: makeFlush(w), alongside: alongside}
+}
+
+type morseWriter struct {
+	w         io.Writer
+	flush     func() error
+	alongside bool
+}
+
+func (m *morseWriter) Write(p []byte) (int, error) {
+	var out []byte
+	for b := p; len(b) > 0; {
+		r, size := utf8.DecodeRune(b)
+		char := b[:size]
+		b = b[size:]
+
+		code, ok := MorseCode(r)
+		switch {
+		case ok && m.alongside:
+			out = append(out, char...)
+			out = append(out, '[')
+			out = append(out, code...)
+			out = append(out, ']')
+		case ok:
+			out = append(out, code...)
+			out = append(out, ' ')
+		case r == ' ' && !m.alongside:
+			out = append(out, "/ "...)
+		default:
+			out = append(out, char...)
+		}
+	}
+
+	if _, err := m.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// flush whatever's underneath
+func (m *morseWriter) Flush() error {
+	return m.flush()
+}
@@ -0,0 +1,75 @@
+package slow_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestMorse(t *testing.T) {
+	// a dit is 60ms at 20 words per minute
+	dit := 60 * time.Millisecond
+	tests := []struct {
+		name string
+		r    rune
+		want time.Duration
+	}{
+		{"dit", 'e', 4 * dit},
+		{"dah", 't', 6 * dit},
+		{"both", 'a', 8 * dit},
+		{"uppercase", 'A', 8 * dit},
+		{"digit", '0', 22 * dit},
+		{"space", ' ', 4 * dit},
+		{"no code", '#', 3 * dit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slow.Morse(20).Delay(tt.r); got != tt.want {
+				t.Errorf("Delay(%q) = %v, want %v", tt.r, got, tt.want)
+			}
+		})
+	}
+}
+
+// by definition, a word per minute is PARIS once a minute
+func TestMorseParis(t *testing.T) {
+	for _, wpm := range []float64{5, 20, 60} {
+		var total time.Duration
+		for _, r := range "PARIS " {
+			total += slow.Morse(wpm).Delay(r)
+		}
+		if want := time.Duration(float64(time.Minute) / wpm); total != want {
+			t.Errorf("PARIS took %v at %v wpm, want %v", total, wpm, want)
+		}
+	}
+}
+
+func TestMorseWriter(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		alongside bool
+		want      string
+	}{
+		{"letters", "sos", false, "... --- ... "},
+		{"words", "e t", false, ". / - "},
+		{"no code", "e\n#", false, ". \n#"},
+		{"alongside", "Hi!", true, "H[....]i[..]![-.-.--]"},
+		{"alongside spaces", "e t\n", true, "e[.] t[-]\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if _, err := slow.NewMorseWriter(&out, tt.alongside).Write([]byte(tt.input)); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("wrote %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package slow
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// A Network is a connection that's slow in all the ways real ones are. It only
+// has so much bandwidth, it stalls every so often, and sometimes latency just
+// spikes for no reason at all.
+type Network struct {
+	// BytesPerSecond is how much fits through the connection.
+	BytesPerSecond float64
+	// Every StallEvery of sending, the connection stalls for Stall. A
+	// StallEvery of zero never stalls.
+	StallEvery time.Duration
+	Stall      time.Duration
+	// Latency spikes about once every SpikeEvery of sending, at random. A
+	// spike waits a uniformly random amount of time between SpikeMin and
+	// SpikeMax. A SpikeEvery of zero never spikes.
+	SpikeEvery time.Duration
+	SpikeMin   time.Duration
+	SpikeMax   time.Duration
+}
+
+// Patience returns Patience that writes like it's going over n. Randomness
+// comes from rnd, exactly like Jitter. Keeping track of when to stall means it
+// can't be shared between Writers.
+func (n Network) Patience(rnd *rand.Rand) Patience {
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	var bandwidth Patience = PatienceFunc(func(rune) time.Duration { return 0 })
+	if n.BytesPerSecond > 0 {
+		bandwidth = BPS(n.BytesPerSecond)
+	}
+	return &network{n: n, p: bandwidth, rnd: rnd}
+}
+
+type network struct {
+	n   Network
+	p   Patience
+	rnd *rand.Rand
+
+	// time spent sending since the last stall
+	sending time.Duration
+}
+
+func (n *network) Delay(r rune) time.Duration {
+	return n.DelayAt(r, Position{Prev: -1})
+}
+
+func (n *network) DelayAt(r rune, pos Position) time.Duration {
+	d := delayAt(n.p, r, pos)
+
+	n.sending += d
+	if n.n.StallEvery > 0 && n.sending >= n.n.StallEvery {
+		n.sending -= n.n.StallEvery
+		d += n.n.Stall
+	}
+
+	if n.n.SpikeEvery > 0 && n.rnd.Float64() < float64(d)/float64(n.n.SpikeEvery) {
+		d += n.n.SpikeMin
+		if n.n.SpikeMax > n.n.SpikeMin {
+			d += time.Duration(n.rnd.Int63n(int64(n.n.SpikeMax - n.n.SpikeMin)))
+		}
+	}
+	return d
+}
+
+func (n *network) Reset() {
+	n.sending = 0
+}
+
+var (
+	networksMu sync.RWMutex
+	networks   = map[string]Network{
+		// a 56k modem on a noisy phone line
+		"dialup": {
+			BytesPerSecond: 5600,
+			StallEvery:     5 * time.Second, Stall: 1 * time.Second,
+			SpikeEvery: 4 * time.Second, SpikeMin: 100 * time.Millisecond, SpikeMax: 400 * time.Millisecond,
+		},
+		// GPRS, with one bar of signal
+		"2g": {
+			BytesPerSecond: 5000,
+			StallEvery:     3 * time.Second, Stall: 2 * time.Second,
+			SpikeEvery: 2 * time.Second, SpikeMin: 300 * time.Millisecond, SpikeMax: 1500 * time.Millisecond,
+		},
+		// plenty of bandwidth, at the other end of a very long round trip
+		"satellite": {
+			BytesPerSecond: 50000,
+			StallEvery:     1 * time.Second, Stall: 1500 * time.Millisecond,
+			SpikeEvery: 5 * time.Second, SpikeMin: 600 * time.Millisecond, SpikeMax: 1200 * time.Millisecond,
+		},
+		// typing into a shell from a moving train
+		"ssh-over-cellular": {
+			BytesPerSecond: 20000,
+			StallEvery:     500 * time.Millisecond, Stall: 3 * time.Second,
+			SpikeEvery: 1 * time.Second, SpikeMin: 200 * time.Millisecond, SpikeMax: 2 * time.Second,
+		},
+	}
+)
+
+// RegisterNetwork makes a Network available by name. It panics if a Network is
+// already registered with the same name.
+func RegisterNetwork(name string, n Network) {
+	networksMu.Lock()
+	defer networksMu.Unlock()
+
+	if _, dup := networks[name]; dup {
+		panic("slow: RegisterNetwork called twice for " + name)
+	}
+	networks[name] = n
+}
+
+// LookupNetwork returns the Network registered with name, if there is one.
+func LookupNetwork(name string) (Network, bool) {
+	networksMu.RLock()
+	defer networksMu.RUnlock()
+
+	n, ok := networks[name]
+	return n, ok
+}
+
+// Networks returns the names of every registered Network, in sorted order.
+func Networks() []string {
+	networksMu.RLock()
+	defer networksMu.RUnlock()
+
+	names := make([]string, 0, len(networks))
+	for name := range networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,90 @@
+package slow_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestNetwork(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name    string
+		network slow.Network
+		input   string
+		want    []time.Duration
+	}{
+		{"unlimited", slow.Network{}, "ab", []time.Duration{0, 0}},
+		{"bandwidth", slow.Network{BytesPerSecond: 100}, "aé", []time.Duration{10 * ms, 20 * ms}},
+		// stalling happens after enough sending, whatever got sent
+		{"stalls", slow.Network{BytesPerSecond: 100, StallEvery: 30 * ms, Stall: time.Second}, "aaaaaa",
+			[]time.Duration{10 * ms, 10 * ms, 1010 * ms, 10 * ms, 10 * ms, 1010 * ms}},
+		{"stalls partway", slow.Network{BytesPerSecond: 100, StallEvery: 25 * ms, Stall: time.Second}, "aaaaa",
+			[]time.Duration{10 * ms, 10 * ms, 1010 * ms, 10 * ms, 1010 * ms}},
+		// sending for a whole SpikeEvery at once always spikes
+		{"spikes", slow.Network{BytesPerSecond: 100, SpikeEvery: 10 * ms, SpikeMin: 500 * ms, SpikeMax: 500 * ms}, "aa",
+			[]time.Duration{510 * ms, 510 * ms}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, sleeps := play(t, []string{tt.input}, slow.WithPatience(tt.network.Patience(rand.New(rand.NewSource(1)))))
+			checkSleeps(t, sleeps, tt.want)
+		})
+	}
+}
+
+func TestNetworkSpikes(t *testing.T) {
+	ms := time.Millisecond
+	n := slow.Network{BytesPerSecond: 100, SpikeEvery: 100 * ms, SpikeMin: 200 * ms, SpikeMax: 300 * ms}
+	_, sleeps := play(t, []string{strings.Repeat("a", 1000)}, slow.WithPatience(n.Patience(rand.New(rand.NewSource(1)))))
+
+	// every byte takes 10ms, so about one in ten spike
+	spikes := 0
+	for _, d := range sleeps {
+		switch {
+		case d == 10*ms:
+		case d >= 210*ms && d < 310*ms:
+			spikes++
+		default:
+			t.Fatalf("waited %v, want 10ms or a spike between 210ms and 310ms", d)
+		}
+	}
+	if spikes < 70 || spikes > 130 {
+		t.Errorf("spiked %d times in 1000 bytes, want about 100", spikes)
+	}
+}
+
+func TestNetworks(t *testing.T) {
+	for _, name := range []string{"2g", "dialup", "satellite", "ssh-over-cellular"} {
+		if _, ok := slow.LookupNetwork(name); !ok {
+			t.Errorf("no %s network", name)
+		}
+	}
+	if _, ok := slow.LookupNetwork("carrier-pigeon"); ok {
+		t.Error("found a network that was never registered")
+	}
+
+	n := slow.Network{BytesPerSecond: 1}
+	slow.RegisterNetwork("test", n)
+	if got, ok := slow.LookupNetwork("test"); !ok || got != n {
+		t.Errorf("LookupNetwork after RegisterNetwork = %v, %v, want %v, true", got, ok, n)
+	}
+
+	names := slow.Networks()
+	for i := 1; i < len(names); i++ {
+		if names[i-1] >= names[i] {
+			t.Fatalf("Networks() = %q, want them sorted", names)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering the same name twice didn't panic")
+		}
+	}()
+	slow.RegisterNetwork("test", n)
+}
@@ -0,0 +1,159 @@
+package slow
+
+import (
+	"context"
+	"regexp"
+	"time"
+)
+
+// An Option configures a Writer created with New.
+type Option func(*config)
+
+type config struct {
+	base      time.Duration
+	step      time.Duration
+	bits      uint
+	patience  Patience
+	flush     func() error
+	tokenizer Tokenizer
+	perRune   bool
+	clock     Clock
+	onRune    func(rune, time.Duration, int)
+	timeout   time.Duration
+	typos     *Typos
+	absolute  bool
+	fast      *regexp.Regexp
+	lines     bool
+	escapes   bool
+	ctx       context.Context
+}
+
+func defaultConfig() config {
+	return config{
+		base:      1 * time.Second,
+		step:      100 * time.Millisecond,
+		bits:      3,
+		tokenizer: Runes,
+		perRune:   true,
+		clock:     realClock{},
+	}
+}
+
+// WithBaseDelay sets the least amount of time to wait after every rune. The
+// default is one second.
+//
+// The base delay, step, and bits are only used to be patient if WithPatience
+// isn't.
+func WithBaseDelay(d time.Duration) Option {
+	return func(c *config) { c.base = d }
+}
+
+// WithStep sets the amount of time to wait for every unit of the low bits of
+// a rune. The default is 100ms.
+func WithStep(d time.Duration) Option {
+	return func(c *config) { c.step = d }
+}
+
+// WithBits sets the number of low bits of a rune used to decide how long to
+// wait. The default is 3. New panics if bits is 8 or more.
+func WithBits(bits uint) Option {
+	return func(c *config) { c.bits = bits }
+}
+
+// WithPatience sets the Patience used to decide how long to wait, instead of
+// the base delay, step, and bits.
+func WithPatience(p Patience) Option {
+	return func(c *config) { c.patience = p }
+}
+
+// WithFlush sets the func called after every token is written. By default,
+// Writers flush anything that looks like it can be flushed or synced. Any
+// error flush returns is returned from Write as a FlushError.
+func WithFlush(flush func() error) Option {
+	return func(c *config) { c.flush = flush }
+}
+
+// WithTokenizer sets how written bytes are split into tokens. The default is
+// Runes.
+func WithTokenizer(t Tokenizer) Option {
+	return func(c *config) { c.tokenizer = t }
+}
+
+// WithLengthScaling sets whether longer tokens take longer. By default, a
+// Writer waits as long as it would for every rune in a token put together.
+// Without scaling, it waits once per token, as long as it would for the first
+// rune.
+func WithLengthScaling(scale bool) Option {
+	return func(c *config) { c.perRune = scale }
+}
+
+// WithClock sets the Clock used to wait. The default is the system clock.
+func WithClock(clock Clock) Option {
+	return func(c *config) { c.clock = clock }
+}
+
+// WithOnRune sets a func that's called with every rune just before it's
+// written, along with how long the Writer is going to wait for it and its
+// position in everything the Writer has written, counting from zero.
+//
+// Runes in the same token are all reported before the token is written, and
+// the Writer waits for all of their delays put together afterwards.
+func WithOnRune(f func(r rune, delay time.Duration, pos int)) Option {
+	return func(c *config) { c.onRune = f }
+}
+
+// WithWriteTimeout gives every write to the underlying writer d to finish, if
+// the underlying writer has a SetWriteDeadline method like a net.Conn does. A
+// write that doesn't finish in time fails with a WriteError that reports
+// itself as a Timeout.
+//
+// The deadline is cleared after every write, so the time spent waiting between
+// writes never counts against it.
+func WithWriteTimeout(d time.Duration) Option {
+	return func(c *config) { c.timeout = d }
+}
+
+// WithAbsoluteTime makes a Writer keep time against the clock instead of just
+// waiting. Every token is written when it's due, as measured from the first
+// one, no matter how long writing and flushing took. Without absolute time,
+// delays are always exact but small amounts of time spent writing add up.
+//
+// A Writer that falls behind catches up by not waiting at all until it's back
+// on schedule.
+func WithAbsoluteTime() Option {
+	return func(c *config) { c.absolute = true }
+}
+
+// WithFastMatch writes anything matching re immediately, without waiting at
+// all, while everything around it stays as patient as ever. Matches are always
+// written as tokens of their own, and they're never mistyped.
+//
+// Matching only happens against what's been written but not yet waited for, so
+// a match that runs right up to the end of a Write is held until the next Write
+// shows whether it keeps going. A match split across Writes before its first
+// byte is written can't be caught at all.
+func WithFastMatch(re *regexp.Regexp) Option {
+	return func(c *config) { c.fast = re }
+}
+
+// WithLineBuffering holds everything written until the end of its line has
+// been written too, so Patience can always see the whole rest of the line in
+// Position.Ahead. Once the Writer is closed, whatever's left is written even if
+// it doesn't end with a newline.
+func WithLineBuffering() Option {
+	return func(c *config) { c.lines = true }
+}
+
+// WithInstantEscapes writes ANSI escape sequences all at once, without waiting
+// for them, so colors and cursor movements never show up half-written. Only
+// the text around them is patient.
+func WithInstantEscapes() Option {
+	return func(c *config) { c.escapes = true }
+}
+
+// WithContext makes a Writer give up as soon as ctx is done. A Write that's
+// waiting stops waiting and returns ctx.Err(), and so does every Write after
+// it.
+func WithContext(ctx context.Context) Option {
+	return func(c *config) { c.ctx = ctx }
+}
@@ -0,0 +1,146 @@
+package slow
+
+import (
+	"hash/crc32"
+	"hash/fnv"
+	"time"
+	"unicode/utf8"
+)
+
+// Patience determines how long to wait after a rune.
+//
+// Patience is allowed to remember what it's seen. Anything that wants to start
+// over should also implement Resetter.
+type Patience interface {
+	Delay(r rune) time.Duration
+}
+
+// A Resetter is Patience that can forget everything it's seen so far.
+type Resetter interface {
+	Reset()
+}
+
+// reset p if it knows how to be reset.
+func reset(p Patience) {
+	if r, ok := p.(Resetter); ok {
+		r.Reset()
+	}
+}
+
+// A Position describes where a rune is in everything a Writer has written.
+// Everything is counted from zero.
+type Position struct {
+	// Rune is the number of runes written before this one, and Offset is the
+	// number of bytes.
+	Rune   int
+	Offset int64
+	// Line is the number of newlines written before this rune, and Column is
+	// the number of runes written since the last one.
+	Line   int
+	Column int
+	// Prev is the rune written just before this one, or -1 if nothing has been
+	// written yet.
+	Prev rune
+	// Ahead is everything that's already known to come after this rune. There
+	// may be more coming that just hasn't been written yet. Ahead is only valid
+	// until Delay or DelayAt returns.
+	Ahead []byte
+}
+
+// move past a rune that took size bytes to write
+func (p *Position) advance(r rune, size int) {
+	p.Rune++
+	p.Offset += int64(size)
+	p.Column++
+	if r == '\n' {
+		p.Line++
+		p.Column = 0
+	}
+	p.Prev = r
+}
+
+// PositionalPatience is Patience that wants to know where a rune is before
+// deciding how long to wait for it. Writers call DelayAt instead of Delay
+// whenever they can.
+type PositionalPatience interface {
+	Patience
+	DelayAt(r rune, pos Position) time.Duration
+}
+
+// ask p how long to wait for r, telling it where r is if it wants to know.
+func delayAt(p Patience, r rune, pos Position) time.Duration {
+	if pp, ok := p.(PositionalPatience); ok {
+		return pp.DelayAt(r, pos)
+	}
+	return p.Delay(r)
+}
+
+// The PositionFunc type is an adapter that allows an ordinary func to be used
+// as PositionalPatience.
+type PositionFunc func(rune, Position) time.Duration
+
+// Delay returns f(r) as if r were the very first rune.
+func (f PositionFunc) Delay(r rune) time.Duration {
+	return f(r, Position{Prev: -1})
+}
+
+// DelayAt returns f(r, pos).
+func (f PositionFunc) DelayAt(r rune, pos Position) time.Duration {
+	return f(r, pos)
+}
+
+// The PatienceFunc type is an adapter that allows an ordinary func to be used
+// as Patience. PatienceFuncs can't remember anything, and can't be reset.
+type PatienceFunc func(rune) time.Duration
+
+// Delay returns f(r).
+func (f PatienceFunc) Delay(r rune) time.Duration {
+	return f(r)
+}
+
+// BePatient returns a Patience that waits initial plus step for every unit of
+// the low bits of a rune. It panics if bits is 8 or more.
+func BePatient(bits uint, initial, step time.Duration) Patience {
+	return BePatientHashed(bits, initial, step, nil)
+}
+
+// BePatientHashed is like BePatient, but uses the low bits of a hash of every
+// rune instead of the rune itself. Code points that are close together (like
+// all of ASCII) tend to get similar delays from BePatient. A good hash spreads
+// them out.
+//
+// If hash is nil, runes aren't hashed at all.
+func BePatientHashed(bits uint, initial, step time.Duration, hash RuneHash) Patience {
+	if bits >= 8 {
+		panic("too many bits")
+	}
+	mask := uint32((0x1 << bits) - 1)
+	if hash == nil {
+		hash = func(r rune) uint32 { return uint32(r) }
+	}
+
+	return PatienceFunc(func(b rune) time.Duration {
+		return initial + step*time.Duration(mask&hash(b))
+	})
+}
+
+// A RuneHash hashes a rune.
+type RuneHash func(rune) uint32
+
+// FNV hashes the UTF-8 encoding of a rune with 32-bit FNV-1a.
+func FNV(r rune) uint32 {
+	h := fnv.New32a()
+	h.Write(encodeRune(r))
+	return h.Sum32()
+}
+
+// CRC32 hashes the UTF-8 encoding of a rune with the IEEE CRC-32 checksum.
+func CRC32(r rune) uint32 {
+	return crc32.ChecksumIEEE(encodeRune(r))
+}
+
+func encodeRune(r rune) []byte {
+	var buf [utf8.UTFMax]byte
+	n := utf8.EncodeRune(buf[:], r)
+	return buf[:n]
+}
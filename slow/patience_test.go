@@ -0,0 +1,103 @@
+package slow_test
+
+import (
+	"hash/crc32"
+	"hash/fnv"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestBePatient(t *testing.T) {
+	tests := []struct {
+		name          string
+		bits          uint
+		initial, step time.Duration
+		r             rune
+		want          time.Duration
+	}{
+		{"no bits", 0, time.Second, 100 * time.Millisecond, 'a', time.Second},
+		{"low bits", 3, time.Second, 100 * time.Millisecond, 'a', 1100 * time.Millisecond},
+		{"all low bits", 3, time.Second, 100 * time.Millisecond, 'g', 1700 * time.Millisecond},
+		{"high bits ignored", 3, time.Second, 100 * time.Millisecond, 'h', time.Second},
+		{"more bits", 7, 0, time.Millisecond, 'a', 97 * time.Millisecond},
+		{"multibyte", 3, 0, time.Millisecond, '世', 6 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := slow.BePatient(tt.bits, tt.initial, tt.step)
+			if got := p.Delay(tt.r); got != tt.want {
+				t.Errorf("Delay(%q) = %v, want %v", tt.r, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBePatientTooManyBits(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("BePatient didn't panic with 8 bits")
+		}
+	}()
+	slow.BePatient(8, 0, 0)
+}
+
+func TestBePatientHashed(t *testing.T) {
+	five := func(rune) uint32 { return 5 }
+
+	tests := []struct {
+		name string
+		hash slow.RuneHash
+		r    rune
+		want time.Duration
+	}{
+		{"no hash", nil, 'a', 1100 * time.Millisecond},
+		{"hash", five, 'a', 1500 * time.Millisecond},
+		{"hash masked", func(rune) uint32 { return 0xff }, 'a', 1700 * time.Millisecond},
+		{"fnv", slow.FNV, 'a', time.Second + 100*time.Millisecond*time.Duration(fnvOf("a")&7)},
+		{"crc32", slow.CRC32, '世', time.Second + 100*time.Millisecond*time.Duration(crc32.ChecksumIEEE([]byte("世"))&7)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := slow.BePatientHashed(3, time.Second, 100*time.Millisecond, tt.hash)
+			if got := p.Delay(tt.r); got != tt.want {
+				t.Errorf("Delay(%q) = %v, want %v", tt.r, got, tt.want)
+			}
+		})
+	}
+}
+
+func fnvOf(s string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return h.Sum32()
+}
+
+func TestPositions(t *testing.T) {
+	var got []slow.Position
+	p := slow.PositionFunc(func(r rune, pos slow.Position) time.Duration {
+		pos.Ahead = append([]byte(nil), pos.Ahead...)
+		got = append(got, pos)
+		return 0
+	})
+	play(t, []string{"é\n", "b"}, slow.WithPatience(p))
+
+	want := []slow.Position{
+		{Rune: 0, Offset: 0, Line: 0, Column: 0, Prev: -1, Ahead: []byte("\n")},
+		// nothing after the first Write is known about yet
+		{Rune: 1, Offset: 2, Line: 0, Column: 1, Prev: 'é', Ahead: []byte{}},
+		{Rune: 2, Offset: 3, Line: 1, Column: 0, Prev: '\n', Ahead: []byte{}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d positions, want %d", len(got), len(want))
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.Rune != w.Rune || g.Offset != w.Offset || g.Line != w.Line || g.Column != w.Column || g.Prev != w.Prev || string(g.Ahead) != string(w.Ahead) {
+			t.Errorf("position %d is %+v, want %+v", i, g, w)
+		}
+	}
+}
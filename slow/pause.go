@@ -0,0 +1,152 @@
+package slow
+
+import (
+	"math/rand"
+	"time"
+	"unicode"
+)
+
+// PauseAfter returns Patience that waits as long as p, plus a little longer
+// after any of the runes in pauses.
+func PauseAfter(p Patience, pauses map[rune]time.Duration) Patience {
+	return &pauseAfter{p: p, pauses: pauses}
+}
+
+// Whitespace returns Patience that waits d after any whitespace, and as long as
+// p says after anything else. A d of zero rushes through whitespace so that
+// only words take any time.
+func Whitespace(p Patience, d time.Duration) Patience {
+	return &whitespace{p: p, d: d}
+}
+
+type whitespace struct {
+	p Patience
+	d time.Duration
+}
+
+func (w *whitespace) Delay(r rune) time.Duration {
+	return w.DelayAt(r, Position{Prev: -1})
+}
+
+func (w *whitespace) DelayAt(r rune, pos Position) time.Duration {
+	if unicode.IsSpace(r) {
+		return w.d
+	}
+	return delayAt(w.p, r, pos)
+}
+
+func (w *whitespace) Reset() {
+	reset(w.p)
+}
+
+// Punctuation returns pauses for PauseAfter that breathe like someone reading
+// aloud: sentence after the end of a sentence or a colon, and clause after a
+// comma or semicolon.
+func Punctuation(sentence, clause time.Duration) map[rune]time.Duration {
+	return map[rune]time.Duration{
+		'.': sentence,
+		'!': sentence,
+		'?': sentence,
+		':': sentence,
+		',': clause,
+		';': clause,
+	}
+}
+
+type pauseAfter struct {
+	p      Patience
+	pauses map[rune]time.Duration
+}
+
+func (p *pauseAfter) Delay(r rune) time.Duration {
+	return p.DelayAt(r, Position{Prev: -1})
+}
+
+func (p *pauseAfter) DelayAt(r rune, pos Position) time.Duration {
+	return delayAt(p.p, r, pos) + p.pauses[r]
+}
+
+func (p *pauseAfter) Reset() {
+	reset(p.p)
+}
+
+// ParagraphPause returns Patience that waits as long as p, plus pause after
+// every blank line, so that paragraphs read like paragraphs. Lines with
+// nothing but whitespace on them count as blank.
+func ParagraphPause(p Patience, pause time.Duration) Patience {
+	return &paragraphPause{p: p, pause: pause}
+}
+
+type paragraphPause struct {
+	p     Patience
+	pause time.Duration
+
+	// true once a line has ended, and true while the current line has only
+	// been whitespace.
+	ended bool
+	blank bool
+}
+
+func (p *paragraphPause) Delay(r rune) time.Duration {
+	return p.DelayAt(r, Position{Prev: -1})
+}
+
+func (p *paragraphPause) DelayAt(r rune, pos Position) time.Duration {
+	d := delayAt(p.p, r, pos)
+
+	switch {
+	case r == '\n':
+		if p.ended && p.blank {
+			d += p.pause
+		}
+		p.ended, p.blank = true, true
+	case !unicode.IsSpace(r):
+		p.blank = false
+	}
+	return d
+}
+
+func (p *paragraphPause) Reset() {
+	reset(p.p)
+	p.ended, p.blank = false, false
+}
+
+// Think returns Patience that waits as long as p, but stops to think every so
+// often between words. After the end of any word, there's a chance of pausing
+// for a uniformly random amount of time between min and max.
+//
+// Randomness comes from rnd, exactly like Jitter.
+func Think(p Patience, chance float64, min, max time.Duration, rnd *rand.Rand) Patience {
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return &think{p: p, chance: chance, min: min, max: max, rnd: rnd}
+}
+
+type think struct {
+	p        Patience
+	chance   float64
+	min, max time.Duration
+	rnd      *rand.Rand
+}
+
+func (t *think) Delay(r rune) time.Duration {
+	return t.DelayAt(r, Position{Prev: -1})
+}
+
+func (t *think) DelayAt(r rune, pos Position) time.Duration {
+	d := delayAt(t.p, r, pos)
+
+	endOfWord := unicode.IsSpace(r) && pos.Prev >= 0 && !unicode.IsSpace(pos.Prev)
+	if endOfWord && t.rnd.Float64() < t.chance {
+		d += t.min
+		if t.max > t.min {
+			d += time.Duration(t.rnd.Int63n(int64(t.max - t.min)))
+		}
+	}
+	return d
+}
+
+func (t *think) Reset() {
+	reset(t.p)
+}
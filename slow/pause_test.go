@@ -0,0 +1,81 @@
+package slow_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+// every wait a Writer with p makes writing input
+func waits(t *testing.T, p slow.Patience, input string) []time.Duration {
+	t.Helper()
+
+	_, sleeps := play(t, []string{input}, slow.WithPatience(p))
+	return sleeps
+}
+
+func TestPauses(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name  string
+		p     slow.Patience
+		input string
+		want  []time.Duration
+	}{
+		{"nothing to pause for", slow.PauseAfter(always(10*ms), nil), "ab", []time.Duration{10 * ms, 10 * ms}},
+		{"pause after", slow.PauseAfter(always(10*ms), map[rune]time.Duration{'b': time.Second}), "ab", []time.Duration{10 * ms, 1010 * ms}},
+		{"punctuation", slow.PauseAfter(always(10*ms), slow.Punctuation(time.Second, 500*ms)), "a. b;c?", []time.Duration{10 * ms, 1010 * ms, 10 * ms, 10 * ms, 510 * ms, 10 * ms, 1010 * ms}},
+		{"paragraph", slow.ParagraphPause(always(10*ms), time.Second), "a\n\nb", []time.Duration{10 * ms, 10 * ms, 1010 * ms, 10 * ms}},
+		{"paragraph of whitespace", slow.ParagraphPause(always(10*ms), time.Second), "a\n \n", []time.Duration{10 * ms, 10 * ms, 10 * ms, 1010 * ms}},
+		{"no paragraph", slow.ParagraphPause(always(10*ms), time.Second), "a\nb\n", []time.Duration{10 * ms, 10 * ms, 10 * ms, 10 * ms}},
+		// a blank line at the very start doesn't end anything
+		{"leading blank line", slow.ParagraphPause(always(10*ms), time.Second), "\na", []time.Duration{10 * ms, 10 * ms}},
+		{"rush whitespace", slow.Whitespace(always(10*ms), 0), "a b\tc", []time.Duration{10 * ms, 0, 10 * ms, 0, 10 * ms}},
+		{"linger on whitespace", slow.Whitespace(always(10*ms), time.Second), "a\n", []time.Duration{10 * ms, time.Second}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkSleeps(t, waits(t, tt.p, tt.input), tt.want)
+		})
+	}
+}
+
+func TestThink(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name     string
+		chance   float64
+		min, max time.Duration
+		input    string
+		// which waits might include some thinking
+		thinking []bool
+	}{
+		{"never", 0, time.Second, 2 * time.Second, "a b", []bool{false, false, false}},
+		{"always", 1, time.Second, 2 * time.Second, "a b ", []bool{false, true, false, true}},
+		// only the end of a word is worth thinking about
+		{"spaces", 1, time.Second, 2 * time.Second, " a  b", []bool{false, false, true, false, false}},
+		{"exactly", 1, time.Second, time.Second, "a b", []bool{false, true, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := slow.Think(always(10*ms), tt.chance, tt.min, tt.max, rand.New(rand.NewSource(1)))
+			sleeps := waits(t, p, tt.input)
+			if len(sleeps) != len(tt.thinking) {
+				t.Fatalf("slept %v", sleeps)
+			}
+			for i, d := range sleeps {
+				min, max := 10*ms, 10*ms
+				if tt.thinking[i] {
+					min, max = min+tt.min, max+tt.max
+				}
+				if d < min || d > max {
+					t.Errorf("wait %d was %v, want between %v and %v", i, d, min, max)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package slow
+
+import (
+	"math/rand"
+	"time"
+)
+
+// PerLine returns Patience that picks a random speed at the start of every
+// line and keeps it until the line's over, waiting as long as p divided by
+// that speed. Speeds are picked uniformly between slowest and fastest, so a
+// slowest of 0.5 and a fastest of 2 means every line is somewhere between
+// twice as patient as p and half as patient.
+//
+// Randomness comes from rnd, exactly like Jitter. A PerLine remembers which
+// line it's on, so it can't be shared between Writers.
+func PerLine(p Patience, slowest, fastest float64, rnd *rand.Rand) Patience {
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return &perLine{p: p, slowest: slowest, fastest: fastest, rnd: rnd, line: -1}
+}
+
+type perLine struct {
+	p                Patience
+	slowest, fastest float64
+	rnd              *rand.Rand
+
+	line  int
+	speed float64
+}
+
+func (l *perLine) Delay(r rune) time.Duration {
+	return l.DelayAt(r, Position{Prev: -1})
+}
+
+func (l *perLine) DelayAt(r rune, pos Position) time.Duration {
+	if pos.Line != l.line {
+		l.line = pos.Line
+		l.speed = l.slowest + l.rnd.Float64()*(l.fastest-l.slowest)
+	}
+	return time.Duration(float64(delayAt(l.p, r, pos)) / l.speed)
+}
+
+func (l *perLine) Reset() {
+	l.line = -1
+	reset(l.p)
+}
@@ -0,0 +1,44 @@
+package slow_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestPerLine(t *testing.T) {
+	base := 100 * time.Millisecond
+
+	t.Run("fixed speed", func(t *testing.T) {
+		_, sleeps := play(t, []string{"ab\ncd"}, slow.WithPatience(slow.PerLine(always(base), 2, 2, nil)))
+		checkSleeps(t, sleeps, []time.Duration{base / 2, base / 2, base / 2, base / 2, base / 2})
+	})
+
+	t.Run("every line", func(t *testing.T) {
+		lines := strings.Repeat("abcd\n", 50)
+		p := slow.PerLine(always(base), 0.5, 2, rand.New(rand.NewSource(1)))
+		_, sleeps := play(t, []string{lines}, slow.WithPatience(p))
+
+		speeds := make(map[time.Duration]bool)
+		for line := 0; line < 50; line++ {
+			// everything on the same line goes at the same speed, newline
+			// and all
+			waits := sleeps[line*5 : line*5+5]
+			for _, d := range waits {
+				if d != waits[0] {
+					t.Fatalf("line %d waited %v, want the same wait for all of it", line, waits)
+				}
+			}
+			if waits[0] < base/2 || waits[0] > 2*base {
+				t.Fatalf("line %d waited %v, want between %v and %v", line, waits[0], base/2, 2*base)
+			}
+			speeds[waits[0]] = true
+		}
+		if len(speeds) < 40 {
+			t.Errorf("only %d different speeds for 50 lines", len(speeds))
+		}
+	})
+}
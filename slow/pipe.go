@@ -0,0 +1,57 @@
+package slow
+
+import "io"
+
+// Pipe creates a synchronous in-memory pipe, like io.Pipe, that's patient
+// about everything that passes through it. Whatever's written to the
+// PipeWriter shows up on the reading side one token at a time, on the
+// schedule a Writer configured by opts would keep.
+func Pipe(opts ...Option) (*io.PipeReader, *PipeWriter) {
+	pr, pw := io.Pipe()
+	return pr, &PipeWriter{
+		w:  New(pw, opts...),
+		pw: pw,
+	}
+}
+
+// A PipeWriter is the writing half of a Pipe.
+type PipeWriter struct {
+	w  *Writer
+	pw *io.PipeWriter
+}
+
+// Write patiently writes p to the pipe. It blocks until every complete token
+// in p has been read and waited for.
+func (w *PipeWriter) Write(p []byte) (int, error) {
+	return w.w.Write(p)
+}
+
+// Skip stops being patient, exactly like Writer.Skip.
+func (w *PipeWriter) Skip() {
+	w.w.Skip()
+}
+
+// SetMultiplier changes how fast writes go, exactly like Writer.SetMultiplier.
+func (w *PipeWriter) SetMultiplier(m float64) {
+	w.w.SetMultiplier(m)
+}
+
+// Stats returns everything written to the pipe so far.
+func (w *PipeWriter) Stats() Stats {
+	return w.w.Stats()
+}
+
+// Close writes anything still held and then closes the pipe. Reads from the
+// other side return io.EOF once everything's been read.
+func (w *PipeWriter) Close() error {
+	return w.CloseWithError(nil)
+}
+
+// CloseWithError writes anything still held and then closes the pipe. Reads
+// from the other side return err once everything's been read, or io.EOF if
+// err is nil.
+func (w *PipeWriter) CloseWithError(err error) error {
+	cerr := w.w.Close()
+	w.pw.CloseWithError(err)
+	return cerr
+}
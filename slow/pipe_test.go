@@ -0,0 +1,109 @@
+package slow_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+	"github.com/blinsay/aslap/slow/slowtest"
+)
+
+func TestPipePacing(t *testing.T) {
+	ms := time.Millisecond
+	clock := slowtest.NewClock(time.Unix(0, 0))
+	pr, pw := slow.Pipe(slow.WithClock(clock), slow.WithPatience(codePoints))
+
+	go func() {
+		io.WriteString(pw, "ab世")
+		pw.Close()
+	}()
+
+	// a token can't be read until the wait after the last one is over, and
+	// the wait after it can't start until it's been read
+	tokens := []string{"a", "b", "世"}
+	delays := []time.Duration{97 * ms, 98 * ms, 0x4e16 * ms}
+	var before time.Duration
+	buf := make([]byte, 64)
+	for i, want := range tokens {
+		n, err := pr.Read(buf)
+		if err != nil {
+			t.Fatalf("error reading %q: %s", want, err)
+		}
+		if got := string(buf[:n]); got != want {
+			t.Fatalf("read %q, want %q", got, want)
+		}
+		if elapsed := clock.Elapsed(); elapsed < before || elapsed > before+delays[i] {
+			t.Errorf("read %q after %v, want between %v and %v", want, elapsed, before, before+delays[i])
+		}
+		before += delays[i]
+	}
+	if _, err := pr.Read(buf); err != io.EOF {
+		t.Errorf("got %v after everything was read, want EOF", err)
+	}
+	checkSleeps(t, clock.Sleeps(), delays)
+}
+
+func TestPipeClose(t *testing.T) {
+	boom := errors.New("boom")
+	tests := []struct {
+		name  string
+		close func(pw *slow.PipeWriter) error
+		want  error
+	}{
+		// ReadAll doesn't count EOF as an error
+		{"close", (*slow.PipeWriter).Close, nil},
+		{"close with error", func(pw *slow.PipeWriter) error { return pw.CloseWithError(boom) }, boom},
+		{"close with nil", func(pw *slow.PipeWriter) error { return pw.CloseWithError(nil) }, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr, pw := slow.Pipe(slow.WithClock(slowtest.NewClock(time.Unix(0, 0))), slow.WithTokenizer(slow.Words))
+
+			// the last word is held until the pipe is closed, and then it's
+			// the last thing read
+			go func() {
+				io.WriteString(pw, "hi th")
+				tt.close(pw)
+			}()
+
+			got, err := io.ReadAll(pr)
+			if string(got) != "hi th" {
+				t.Errorf("read %q, want %q", got, "hi th")
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("got error %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPipeReaderClosed(t *testing.T) {
+	boom := errors.New("boom")
+	tests := []struct {
+		name  string
+		close func(pr *io.PipeReader) error
+		want  error
+	}{
+		{"close", (*io.PipeReader).Close, io.ErrClosedPipe},
+		{"close with error", func(pr *io.PipeReader) error { return pr.CloseWithError(boom) }, boom},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr, pw := slow.Pipe(slow.WithClock(slowtest.NewClock(time.Unix(0, 0))))
+			tt.close(pr)
+
+			_, err := io.WriteString(pw, "ab")
+			var werr *slow.WriteError
+			if !errors.As(err, &werr) {
+				t.Fatalf("got error %v, want a WriteError", err)
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("got error %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package slow
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// A Preset is a named way of being patient.
+type Preset struct {
+	// Base, Step, Bits, and Hash are passed to BePatientHashed.
+	Base time.Duration
+	Step time.Duration
+	Bits uint
+	Hash RuneHash
+	// Jitter is passed to Jitter, if it's not zero.
+	Jitter time.Duration
+	// Tokenizer is how output is split up. If it's nil, output is split into
+	// Runes.
+	Tokenizer Tokenizer
+}
+
+// Patience returns the Patience described by p.
+func (p Preset) Patience() Patience {
+	patience := BePatientHashed(p.Bits, p.Base, p.Step, p.Hash)
+	if p.Jitter != 0 {
+		patience = Jitter(patience, p.Jitter, nil)
+	}
+	return patience
+}
+
+// Options returns the Options that configure a Writer to be patient the way p
+// describes.
+func (p Preset) Options() []Option {
+	opts := []Option{WithPatience(p.Patience())}
+	if p.Tokenizer != nil {
+		opts = append(opts, WithTokenizer(p.Tokenizer))
+	}
+	return opts
+}
+
+var (
+	presetsMu sync.RWMutex
+	presets   = map[string]Preset{
+		// the default, as slow as possible
+		"aslap": {Base: 1 * time.Second, Step: 100 * time.Millisecond, Bits: 3},
+		// an ASR-33 teletype, at ten characters a second
+		"teletype": {Base: 100 * time.Millisecond},
+		// someone who's pretty good with a typewriter
+		"typewriter": {Base: 70 * time.Millisecond, Step: 10 * time.Millisecond, Bits: 3, Jitter: 40 * time.Millisecond},
+		// a 2400 baud modem
+		"dialup": {Base: 4 * time.Millisecond, Jitter: 1 * time.Millisecond},
+		// a ponderous narrator
+		"narrator": {Base: 250 * time.Millisecond, Step: 50 * time.Millisecond, Bits: 2, Tokenizer: Words},
+	}
+)
+
+// RegisterPreset makes a Preset available by name. It panics if a Preset is
+// already registered with the same name.
+func RegisterPreset(name string, p Preset) {
+	presetsMu.Lock()
+	defer presetsMu.Unlock()
+
+	if _, dup := presets[name]; dup {
+		panic("slow: RegisterPreset called twice for " + name)
+	}
+	presets[name] = p
+}
+
+// LookupPreset returns the Preset registered with name, if there is one.
+func LookupPreset(name string) (Preset, bool) {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+
+	p, ok := presets[name]
+	return p, ok
+}
+
+// Presets returns the names of every registered Preset, in sorted order.
+func Presets() []string {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,60 @@
+package slow_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestLookupPreset(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name string
+		ok   bool
+		want []time.Duration
+	}{
+		{"aslap", true, []time.Duration{1100 * ms, 1200 * ms}},
+		{"teletype", true, []time.Duration{100 * ms, 100 * ms}},
+		// one word, waiting for both of its runes
+		{"narrator", true, []time.Duration{(250 + 50 + 250 + 100) * ms}},
+		{"nope", false, nil},
+		{"", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := slow.LookupPreset(tt.name)
+			if ok != tt.ok {
+				t.Fatalf("LookupPreset(%q) found %v, want %v", tt.name, ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			_, sleeps := play(t, []string{"ab"}, p.Options()...)
+			checkSleeps(t, sleeps, tt.want)
+		})
+	}
+}
+
+func TestRegisterPreset(t *testing.T) {
+	slow.RegisterPreset("test-instant", slow.Preset{})
+	if _, ok := slow.LookupPreset("test-instant"); !ok {
+		t.Fatal("couldn't find a preset that was just registered")
+	}
+
+	found := false
+	for _, name := range slow.Presets() {
+		found = found || name == "test-instant"
+	}
+	if !found {
+		t.Errorf("Presets() = %v, which is missing test-instant", slow.Presets())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering the same preset twice didn't panic")
+		}
+	}()
+	slow.RegisterPreset("test-instant", slow.Preset{})
+}
@@ -0,0 +1,54 @@
+package slow
+
+import "time"
+
+// An Easing maps how far along something is, from 0 to 1, to how far it's
+// moved between where it started and where it's going, also from 0 to 1.
+type Easing func(t float64) float64
+
+// Some common Easings.
+var (
+	Linear    Easing = func(t float64) float64 { return t }
+	EaseIn    Easing = func(t float64) float64 { return t * t }
+	EaseOut   Easing = func(t float64) float64 { return 1 - (1-t)*(1-t) }
+	EaseInOut Easing = func(t float64) float64 {
+		if t < 0.5 {
+			return 2 * t * t
+		}
+		return 1 - 2*(1-t)*(1-t)
+	}
+)
+
+// Ramp returns Patience that waits as long as p, scaled by a factor that
+// moves from from to to over the first window runes, following ease. By the
+// last of those runes, the factor has reached to, and it stays there.
+//
+// A from of 4 and a to of 1 starts four times as patient as p and gradually
+// comes down to exactly as patient.
+func Ramp(p Patience, from, to float64, window int, ease Easing) Patience {
+	return &ramp{p: p, from: from, to: to, window: window, ease: ease}
+}
+
+type ramp struct {
+	p        Patience
+	from, to float64
+	window   int
+	ease     Easing
+}
+
+func (r *ramp) Delay(rn rune) time.Duration {
+	return r.DelayAt(rn, Position{Prev: -1})
+}
+
+func (r *ramp) DelayAt(rn rune, pos Position) time.Duration {
+	t := 1.0
+	if r.window > 1 && pos.Rune < r.window-1 {
+		t = float64(pos.Rune) / float64(r.window-1)
+	}
+	factor := r.from + (r.to-r.from)*r.ease(t)
+	return time.Duration(float64(delayAt(r.p, rn, pos)) * factor)
+}
+
+func (r *ramp) Reset() {
+	reset(r.p)
+}
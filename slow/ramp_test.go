@@ -0,0 +1,34 @@
+package slow_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestRamp(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name   string
+		window int
+		ease   slow.Easing
+		want   []time.Duration
+	}{
+		// from four times as patient to not patient at all, over five runes,
+		// and then staying there
+		{"linear", 5, slow.Linear, []time.Duration{400 * ms, 300 * ms, 200 * ms, 100 * ms, 0, 0}},
+		{"ease in", 5, slow.EaseIn, []time.Duration{400 * ms, 375 * ms, 300 * ms, 175 * ms, 0, 0}},
+		{"ease out", 5, slow.EaseOut, []time.Duration{400 * ms, 225 * ms, 100 * ms, 25 * ms, 0, 0}},
+		{"ease in and out", 5, slow.EaseInOut, []time.Duration{400 * ms, 350 * ms, 200 * ms, 50 * ms, 0, 0}},
+		// there's no ramping over a single rune
+		{"no window", 1, slow.Linear, []time.Duration{0, 0, 0, 0, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := slow.Ramp(always(100*ms), 4, 0, tt.window, tt.ease)
+			checkSleeps(t, waits(t, p, "abcdef"), tt.want)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package slow
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+// CPS returns Patience that writes cps characters per second. It panics if cps
+// isn't positive.
+func CPS(cps float64) Patience {
+	if cps <= 0 {
+		panic("rate must be positive")
+	}
+	delay := time.Duration(float64(time.Second) / cps)
+
+	return PatienceFunc(func(rune) time.Duration {
+		return delay
+	})
+}
+
+// WPM returns Patience that writes wpm words per minute, by the usual
+// convention that a word is five characters. It panics if wpm isn't positive.
+func WPM(wpm float64) Patience {
+	if wpm <= 0 {
+		panic("rate must be positive")
+	}
+	return CPS(wpm * 5 / 60)
+}
+
+// BPS returns Patience that writes bps bytes per second, by waiting for every
+// byte of every rune's UTF-8 encoding. It panics if bps isn't positive.
+func BPS(bps float64) Patience {
+	if bps <= 0 {
+		panic("rate must be positive")
+	}
+	perByte := float64(time.Second) / bps
+
+	return PatienceFunc(func(r rune) time.Duration {
+		return time.Duration(perByte * float64(runeLen(r)))
+	})
+}
+
+// Baud returns Patience that writes like a serial line or a modem at baud
+// symbols per second. Every byte is framed 8N1, with a start bit and a stop
+// bit, so it takes ten bits to send one: 2400 baud is 240 bytes per second.
+// It panics if baud isn't positive.
+func Baud(baud float64) Patience {
+	if baud <= 0 {
+		panic("rate must be positive")
+	}
+	return BPS(baud / 10)
+}
+
+// Metronome returns Patience that waits one note for everything, at bpm beats
+// per minute with subdivision notes to a beat. A subdivision of 4 in 4/4 time
+// is a sixteenth note. It panics if bpm or subdivision aren't positive.
+//
+// Use a Metronome with WithAbsoluteTime to stay on the beat.
+func Metronome(bpm, subdivision float64) Patience {
+	if bpm <= 0 || subdivision <= 0 {
+		panic("rate must be positive")
+	}
+	return CPS(bpm * subdivision / 60)
+}
+
+// how many bytes it takes to write r. runes that can't be encoded are written
+// as U+FFFD.
+func runeLen(r rune) int {
+	if n := utf8.RuneLen(r); n > 0 {
+		return n
+	}
+	return utf8.RuneLen(utf8.RuneError)
+}
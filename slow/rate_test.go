@@ -0,0 +1,59 @@
+package slow_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestRates(t *testing.T) {
+	tests := []struct {
+		name string
+		p    slow.Patience
+		r    rune
+		want time.Duration
+	}{
+		{"cps", slow.CPS(4), 'a', 250 * time.Millisecond},
+		{"wpm", slow.WPM(60), 'a', 200 * time.Millisecond},
+		{"bps ascii", slow.BPS(10), 'a', 100 * time.Millisecond},
+		{"bps multibyte", slow.BPS(10), '世', 300 * time.Millisecond},
+		{"bps emoji", slow.BPS(10), '🐢', 400 * time.Millisecond},
+		// ten bits to a byte, with the start and stop bits
+		{"baud", slow.Baud(2400), 'a', time.Second / 240},
+		{"baud multibyte", slow.Baud(300), 'é', 2 * time.Second / 30},
+		// sixteenth notes at 120 beats per minute
+		{"metronome", slow.Metronome(120, 4), 'a', 125 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.Delay(tt.r); got != tt.want {
+				t.Errorf("Delay(%q) = %v, want %v", tt.r, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRatesMustBePositive(t *testing.T) {
+	rates := map[string]func(){
+		"cps":         func() { slow.CPS(0) },
+		"wpm":         func() { slow.WPM(-1) },
+		"bps":         func() { slow.BPS(0) },
+		"baud":        func() { slow.Baud(0) },
+		"bpm":         func() { slow.Metronome(0, 1) },
+		"subdivision": func() { slow.Metronome(120, 0) },
+		"tps":         func() { slow.Chat(0, nil) },
+	}
+
+	for name, rate := range rates {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("didn't panic")
+				}
+			}()
+			rate()
+		})
+	}
+}
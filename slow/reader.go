@@ -0,0 +1,150 @@
+package slow
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"unicode/utf8"
+)
+
+// A Reader patiently reads runes from an underlying io.Reader, waiting before
+// handing each one to the caller.
+//
+// Every call to Read returns at most one rune. A rune that doesn't fit in the
+// buffer passed to Read is returned across as many calls as it takes, without
+// waiting again.
+type Reader struct {
+	clock    Clock
+	r        io.Reader
+	patience Patience
+	chunk    []byte
+	buf      []byte
+	pending  []byte
+	err      error
+	pos      Position
+}
+
+// NewReader returns a Reader that reads from r, waiting as long as p says
+// before returning every rune.
+func NewReader(r io.Reader, p Patience) *Reader {
+	return &Reader{
+		clock:    realClock{},
+		r:        r,
+		patience: p,
+		pos:      Position{Prev: -1},
+	}
+}
+
+// SetClock sets the Clock the Reader uses to wait. Readers use the system
+// clock by default.
+func (r *Reader) SetClock(c Clock) {
+	r.clock = c
+}
+
+// Read patiently reads the next rune into p. If reading from the underlying
+// reader fails, Read returns a ReadError.
+func (r *Reader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if len(r.pending) == 0 {
+		token, err := r.next()
+		if err != nil {
+			return 0, err
+		}
+
+		if err := r.wait(token); err != nil {
+			return 0, err
+		}
+		r.pending = token
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// WriteTo patiently writes every rune to w until there's nothing left to read,
+// without any intermediate buffering. Every rune is written and flushed on its
+// own, exactly when Read would have returned it. Errors writing to w are
+// returned as WriteErrors, and errors flushing it are returned as FlushErrors.
+func (r *Reader) WriteTo(w io.Writer) (int64, error) {
+	flush := makeFlush(w)
+
+	var n int64
+	if len(r.pending) > 0 {
+		m, err := w.Write(r.pending)
+		n += int64(m)
+		r.pending = r.pending[m:]
+		if err != nil {
+			return n, &WriteError{err}
+		}
+		if err := flush(); err != nil {
+			return n, &FlushError{err}
+		}
+	}
+
+	for {
+		token, err := r.next()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+
+		if err := r.wait(token); err != nil {
+			return n, err
+		}
+
+		m, err := w.Write(token)
+		n += int64(m)
+		if err != nil {
+			return n, &WriteError{err}
+		}
+		if err := flush(); err != nil {
+			return n, &FlushError{err}
+		}
+	}
+}
+
+// wait as long as it takes to be patient about a rune
+func (r *Reader) wait(token []byte) error {
+	rn, size := utf8.DecodeRune(token)
+
+	pos := r.pos
+	pos.Ahead = r.buf
+	delay := delayAt(r.patience, rn, pos)
+	r.pos.advance(rn, size)
+
+	return r.clock.Sleep(context.Background(), delay)
+}
+
+// read the next rune from the underlying reader. once the underlying reader
+// returns an error, any remaining bytes are returned before the error is.
+func (r *Reader) next() ([]byte, error) {
+	if r.chunk == nil {
+		r.chunk = make([]byte, 4096)
+	}
+
+	for {
+		if len(r.buf) > 0 {
+			advance, token, _ := bufio.ScanRunes(r.buf, r.err != nil)
+			if advance > 0 {
+				r.buf = r.buf[advance:]
+				return token, nil
+			}
+		}
+		if r.err != nil {
+			return nil, r.err
+		}
+
+		n, err := r.r.Read(r.chunk)
+		r.buf = append(r.buf, r.chunk[:n]...)
+		if err != nil && err != io.EOF {
+			err = &ReadError{err}
+		}
+		r.err = err
+	}
+}
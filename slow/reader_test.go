@@ -0,0 +1,108 @@
+package slow_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+	"github.com/blinsay/aslap/slow/slowtest"
+)
+
+// waits as many milliseconds as a rune's code point
+var codePoints = slow.PatienceFunc(func(r rune) time.Duration {
+	return time.Duration(r) * time.Millisecond
+})
+
+func TestReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		size  int
+		reads []string
+		want  []time.Duration
+	}{
+		{"nothing", "", 8, nil, nil},
+		{"ascii", "ab", 8, []string{"a", "b"}, []time.Duration{97 * time.Millisecond, 98 * time.Millisecond}},
+		{"multibyte", "aé", 8, []string{"a", "é"}, []time.Duration{97 * time.Millisecond, 233 * time.Millisecond}},
+		// a rune that doesn't fit comes back a piece at a time, waiting once
+		{"small buffer", "é!", 1, []string{"\xc3", "\xa9", "!"}, []time.Duration{233 * time.Millisecond, 33 * time.Millisecond}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := slowtest.NewClock(time.Unix(0, 0))
+			r := slow.NewReader(strings.NewReader(tt.input), codePoints)
+			r.SetClock(clock)
+
+			var reads []string
+			buf := make([]byte, tt.size)
+			for {
+				n, err := r.Read(buf)
+				if n > 0 {
+					reads = append(reads, string(buf[:n]))
+				}
+				if err != nil {
+					break
+				}
+			}
+
+			if strings.Join(reads, "|") != strings.Join(tt.reads, "|") {
+				t.Errorf("read %q, want %q", reads, tt.reads)
+			}
+			checkSleeps(t, clock.Sleeps(), tt.want)
+		})
+	}
+}
+
+// a writer that remembers every Write on its own
+type writes []string
+
+func (w *writes) Write(p []byte) (int, error) {
+	*w = append(*w, string(p))
+	return len(p), nil
+}
+
+func TestReaderWriteTo(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		// how much to Read before WriteTo
+		read   int
+		writes []string
+		want   []time.Duration
+	}{
+		{"nothing", "", 0, nil, nil},
+		{"ascii", "ab", 0, []string{"a", "b"}, []time.Duration{97 * time.Millisecond, 98 * time.Millisecond}},
+		// whatever's left of a rune that was partly read comes out first,
+		// without waiting for it again
+		{"after a partial read", "é!", 1, []string{"\xa9", "!"}, []time.Duration{233 * time.Millisecond, 33 * time.Millisecond}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := slowtest.NewClock(time.Unix(0, 0))
+			r := slow.NewReader(strings.NewReader(tt.input), codePoints)
+			r.SetClock(clock)
+
+			if tt.read > 0 {
+				if _, err := r.Read(make([]byte, tt.read)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var got writes
+			n, err := r.WriteTo(&got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := int64(len(tt.input) - tt.read); n != want {
+				t.Errorf("WriteTo says it wrote %d bytes, want %d", n, want)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.writes, "|") {
+				t.Errorf("wrote %q, want %q", got, tt.writes)
+			}
+			checkSleeps(t, clock.Sleeps(), tt.want)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package slow
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Record writes everything read from r the way a Writer configured by opts
+// would, without ever waiting, and returns a Plan that writes it all again at
+// exactly the same pace. Every write the Writer would have made is a Chunk of
+// its own, including typos and whatever fixes them.
+//
+// Any Clock or flush in opts is ignored.
+func Record(r io.Reader, opts ...Option) (Plan, error) {
+	rec := &recorder{}
+	w := New(rec, append(opts, WithClock(rec), WithFlush(func() error { return nil }))...)
+
+	if _, err := w.ReadFrom(r); err != nil {
+		return rec.plan, err
+	}
+	if err := w.Close(); err != nil {
+		return rec.plan, err
+	}
+	// waiting after the last write counts too
+	rec.plan.wait(rec.now.Sub(rec.last))
+	return rec.plan, nil
+}
+
+// a Clock that never waits, and the io.Writer that remembers when everything
+// would have been written
+type recorder struct {
+	now  time.Time
+	last time.Time
+	plan Plan
+}
+
+func (r *recorder) Now() time.Time {
+	return r.now
+}
+
+func (r *recorder) Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if d > 0 {
+		r.now = r.now.Add(d)
+	}
+	return nil
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	r.plan.wait(r.now.Sub(r.last))
+	r.last = r.now
+	r.plan.add(Chunk{Data: append([]byte(nil), p...)})
+	return len(p), nil
+}
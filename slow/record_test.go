@@ -0,0 +1,93 @@
+package slow_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+	"github.com/blinsay/aslap/slow/slowtest"
+)
+
+func TestRecord(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name  string
+		input string
+		opts  []slow.Option
+		want  slow.Plan
+	}{
+		{"nothing", "", nil, slow.Plan{}},
+		{"runes", "ab", []slow.Option{slow.WithPatience(codePoints)}, slow.Plan{
+			Chunks: []slow.Chunk{{[]byte("a"), 97 * ms}, {[]byte("b"), 98 * ms}},
+			Total:  195 * ms,
+		}},
+		{"words", "hi to", []slow.Option{slow.WithPatience(codePoints), slow.WithTokenizer(slow.Words)}, slow.Plan{
+			Chunks: []slow.Chunk{{[]byte("hi "), (104 + 105 + 32) * ms}, {[]byte("to"), (116 + 111) * ms}},
+			Total:  468 * ms,
+		}},
+		// a clock of its own is no use to a recording
+		{"clock", "ab", []slow.Option{slow.WithPatience(codePoints), slow.WithClock(slowtest.NewClock(time.Unix(0, 0)))}, slow.Plan{
+			Chunks: []slow.Chunk{{[]byte("a"), 97 * ms}, {[]byte("b"), 98 * ms}},
+			Total:  195 * ms,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := slow.Record(strings.NewReader(tt.input), tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkPlan(t, plan, tt.want)
+		})
+	}
+}
+
+func TestWriteCast(t *testing.T) {
+	ms := time.Millisecond
+	plan := slow.Plan{
+		Chunks: []slow.Chunk{{nil, 100 * ms}, {[]byte("hi"), 250 * ms}, {[]byte("you"), time.Second}},
+		Total:  1350 * ms,
+	}
+
+	var cast bytes.Buffer
+	if err := plan.WriteCast(&cast, 80, 24); err != nil {
+		t.Fatal(err)
+	}
+	lines := bufio.NewScanner(&cast)
+
+	var header struct {
+		Version int               `json:"version"`
+		Width   int               `json:"width"`
+		Height  int               `json:"height"`
+		Env     map[string]string `json:"env"`
+	}
+	if !lines.Scan() {
+		t.Fatal("no header")
+	}
+	if err := json.Unmarshal(lines.Bytes(), &header); err != nil {
+		t.Fatal(err)
+	}
+	if header.Version != 2 || header.Width != 80 || header.Height != 24 {
+		t.Errorf("got header %+v, want version 2 at 80x24", header)
+	}
+
+	var events [][]interface{}
+	for lines.Scan() {
+		var event []interface{}
+		if err := json.Unmarshal(lines.Bytes(), &event); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, event)
+	}
+	// the pause at the end gets an empty event so it isn't lost
+	want := [][]interface{}{{0.1, "o", "hi"}, {0.35, "o", "you"}, {1.35, "o", ""}}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("got events %v, want %v", events, want)
+	}
+}
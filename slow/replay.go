@@ -0,0 +1,171 @@
+package slow
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseScriptTiming reads a recording made by script(1) with timing, like
+// script -t 2>timing, as a Plan that replays it at exactly the pace it was
+// recorded. typescript is everything script recorded, and timing is the timing
+// file that goes with it.
+//
+// Every line of timing is a delay in seconds and a number of bytes, optionally
+// after an O for output like script's newer timing format writes. Input and
+// everything else is ignored, since there's nothing to see. The header line
+// script writes at the top of typescript is skipped, if it's there.
+func ParseScriptTiming(timing, typescript io.Reader) (Plan, error) {
+	data := bufio.NewReader(typescript)
+	if header, err := data.Peek(len("Script started")); err == nil && string(header) == "Script started" {
+		if _, err := data.ReadString('\n'); err != nil {
+			return Plan{}, err
+		}
+	}
+
+	var plan Plan
+	scanner := bufio.NewScanner(timing)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) == 3 {
+			if fields[0] != "O" {
+				continue
+			}
+			fields = fields[1:]
+		}
+		if len(fields) != 2 {
+			return plan, fmt.Errorf("line %d: expected a delay and a length", line)
+		}
+
+		secs, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil || secs < 0 {
+			return plan, fmt.Errorf("line %d: invalid delay: %s", line, fields[0])
+		}
+		n, err := strconv.Atoi(fields[1])
+		if err != nil || n < 0 {
+			return plan, fmt.Errorf("line %d: invalid length: %s", line, fields[1])
+		}
+
+		chunk := make([]byte, n)
+		if _, err := io.ReadFull(data, chunk); err != nil {
+			return plan, fmt.Errorf("line %d: error reading typescript: %s", line, err)
+		}
+		// the delay is how long script waited before the chunk, not after
+		plan.wait(time.Duration(secs * float64(time.Second)))
+		plan.add(Chunk{Data: chunk})
+	}
+	return plan, scanner.Err()
+}
+
+// ParseTtyrec reads a recording made by ttyrec(1) as a Plan that replays it at
+// exactly the pace it was recorded.
+func ParseTtyrec(r io.Reader) (Plan, error) {
+	var plan Plan
+	var last time.Duration
+	for frame := 0; ; frame++ {
+		var header struct{ Sec, Usec, Len uint32 }
+		err := binary.Read(r, binary.LittleEndian, &header)
+		if err == io.EOF {
+			return plan, nil
+		}
+		if err != nil {
+			return plan, fmt.Errorf("frame %d: %s", frame, err)
+		}
+
+		data, err := ioutil.ReadAll(io.LimitReader(r, int64(header.Len)))
+		if err != nil {
+			return plan, fmt.Errorf("frame %d: %s", frame, err)
+		}
+		if len(data) < int(header.Len) {
+			return plan, fmt.Errorf("frame %d: %s", frame, io.ErrUnexpectedEOF)
+		}
+
+		at := time.Duration(header.Sec)*time.Second + time.Duration(header.Usec)*time.Microsecond
+		if frame > 0 && at > last {
+			plan.wait(at - last)
+		}
+		last = at
+		plan.add(Chunk{Data: data})
+	}
+}
+
+// WriteScript writes p the way script -t would have recorded it, as a
+// typescript and the timing file that goes with it, so scriptreplay(1) can
+// play it back. Every Chunk is written with how long to wait before it, so a
+// pause at the very end has nowhere to go and is left out.
+func (p Plan) WriteScript(typescript, timing io.Writer) error {
+	// scriptreplay always skips the first line of a typescript
+	header := fmt.Sprintf("Script started on %s\n", time.Now().Format(time.RFC1123))
+	if _, err := io.WriteString(typescript, header); err != nil {
+		return err
+	}
+
+	var wait time.Duration
+	for _, c := range p.Chunks {
+		if len(c.Data) > 0 {
+			if _, err := fmt.Fprintf(timing, "%.6f %d\n", wait.Seconds(), len(c.Data)); err != nil {
+				return err
+			}
+			if _, err := typescript.Write(c.Data); err != nil {
+				return err
+			}
+			wait = 0
+		}
+		wait += c.Delay
+	}
+	return nil
+}
+
+// WriteTtyrec writes p as a ttyrec(1) recording that starts at start. Every
+// Chunk is a frame of its own, timestamped with when it'd be written, and a
+// pause at the very end gets an empty frame so it isn't lost.
+func (p Plan) WriteTtyrec(w io.Writer, start time.Time) error {
+	frame := func(at time.Time, data []byte) error {
+		header := struct{ Sec, Usec, Len uint32 }{
+			uint32(at.Unix()),
+			uint32(at.Nanosecond() / 1000),
+			uint32(len(data)),
+		}
+		if err := binary.Write(w, binary.LittleEndian, header); err != nil {
+			return err
+		}
+		_, err := w.Write(data)
+		return err
+	}
+
+	at, last := start, start
+	for _, c := range p.Chunks {
+		if len(c.Data) > 0 {
+			if err := frame(at, c.Data); err != nil {
+				return err
+			}
+			last = at
+		}
+		at = at.Add(c.Delay)
+	}
+	if at.After(last) {
+		return frame(at, nil)
+	}
+	return nil
+}
+
+// wait a little longer after whatever the last Chunk was
+func (p *Plan) wait(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	if len(p.Chunks) == 0 {
+		p.add(Chunk{Delay: d})
+		return
+	}
+	p.Chunks[len(p.Chunks)-1].Delay += d
+	p.Total += d
+}
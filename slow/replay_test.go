@@ -0,0 +1,174 @@
+package slow_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestParseScriptTiming(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name       string
+		timing     string
+		typescript string
+		want       slow.Plan
+	}{
+		{"nothing", "", "", slow.Plan{}},
+		{"classic", "0.5 2\n0.25 3\n", "hiyou", slow.Plan{
+			Chunks: []slow.Chunk{{nil, 500 * ms}, {[]byte("hi"), 250 * ms}, {[]byte("you"), 0}},
+			Total:  750 * ms,
+		}},
+		{"header", "0 2\n0.25 3\n", "Script started on whenever\nhiyou", slow.Plan{
+			Chunks: []slow.Chunk{{[]byte("hi"), 250 * ms}, {[]byte("you"), 0}},
+			Total:  250 * ms,
+		}},
+		// input doesn't show up in the typescript at all
+		{"advanced", "O 0 2\nI 0.1 1\nO 0.25 3\n", "hiyou", slow.Plan{
+			Chunks: []slow.Chunk{{[]byte("hi"), 250 * ms}, {[]byte("you"), 0}},
+			Total:  250 * ms,
+		}},
+		{"blank lines", "0 2\n\n0 3\n", "hiyou", slow.Plan{
+			Chunks: []slow.Chunk{{[]byte("hi"), 0}, {[]byte("you"), 0}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := slow.ParseScriptTiming(strings.NewReader(tt.timing), strings.NewReader(tt.typescript))
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkPlan(t, plan, tt.want)
+		})
+	}
+}
+
+func TestParseScriptTimingErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		timing     string
+		typescript string
+		want       string
+	}{
+		{"one field", "0.5\n", "hi", "line 1: expected a delay and a length"},
+		{"bad delay", "0 1\nsoon 1\n", "hi", "line 2: invalid delay: soon"},
+		{"negative delay", "-1 1\n", "hi", "line 1: invalid delay: -1"},
+		{"bad length", "0 lots\n", "hi", "line 1: invalid length: lots"},
+		{"short typescript", "0 3\n", "hi", "line 1: error reading typescript: unexpected EOF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := slow.ParseScriptTiming(strings.NewReader(tt.timing), strings.NewReader(tt.typescript))
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// a ttyrec frame written at sec seconds and usec microseconds
+func frame(sec, usec uint32, data string) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, struct{ Sec, Usec, Len uint32 }{sec, usec, uint32(len(data))})
+	buf.WriteString(data)
+	return buf.Bytes()
+}
+
+func TestParseTtyrec(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name   string
+		frames [][]byte
+		want   slow.Plan
+	}{
+		{"nothing", nil, slow.Plan{}},
+		{"one frame", [][]byte{frame(100, 0, "hi")}, slow.Plan{
+			Chunks: []slow.Chunk{{[]byte("hi"), 0}},
+		}},
+		// only the time between frames matters
+		{"frames", [][]byte{frame(100, 0, "hi"), frame(100, 250000, "you"), frame(101, 250000, "!")}, slow.Plan{
+			Chunks: []slow.Chunk{{[]byte("hi"), 250 * ms}, {[]byte("you"), time.Second}, {[]byte("!"), 0}},
+			Total:  1250 * ms,
+		}},
+		{"backwards", [][]byte{frame(100, 0, "hi"), frame(99, 0, "you")}, slow.Plan{
+			Chunks: []slow.Chunk{{[]byte("hi"), 0}, {[]byte("you"), 0}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := slow.ParseTtyrec(bytes.NewReader(bytes.Join(tt.frames, nil)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkPlan(t, plan, tt.want)
+		})
+	}
+}
+
+func TestParseTtyrecErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"short header", frame(100, 0, "hi")[:6], "frame 0: unexpected EOF"},
+		{"short frame", append(frame(100, 0, "hi"), frame(101, 0, "you")[:14]...), "frame 1: unexpected EOF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := slow.ParseTtyrec(bytes.NewReader(tt.data))
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplayRoundTrip(t *testing.T) {
+	ms := time.Millisecond
+	plan := slow.Plan{
+		Chunks: []slow.Chunk{{nil, 100 * ms}, {[]byte("hi"), 250 * ms}, {[]byte("you"), time.Second}},
+		Total:  1350 * ms,
+	}
+
+	t.Run("script", func(t *testing.T) {
+		var typescript, timing bytes.Buffer
+		if err := plan.WriteScript(&typescript, &timing); err != nil {
+			t.Fatal(err)
+		}
+		got, err := slow.ParseScriptTiming(&timing, &typescript)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the pause at the very end has nowhere to go
+		checkPlan(t, got, slow.Plan{
+			Chunks: []slow.Chunk{{nil, 100 * ms}, {[]byte("hi"), 250 * ms}, {[]byte("you"), 0}},
+			Total:  350 * ms,
+		})
+	})
+
+	t.Run("ttyrec", func(t *testing.T) {
+		var rec bytes.Buffer
+		if err := plan.WriteTtyrec(&rec, time.Unix(100, 0)); err != nil {
+			t.Fatal(err)
+		}
+		got, err := slow.ParseTtyrec(&rec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the pause at the very start is lost, but the one at the end gets
+		// an empty frame of its own
+		checkPlan(t, got, slow.Plan{
+			Chunks: []slow.Chunk{{[]byte("hi"), 250 * ms}, {[]byte("you"), time.Second}, {nil, 0}},
+			Total:  1250 * ms,
+		})
+	})
+}
@@ -0,0 +1,83 @@
+package slow
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Schedule is a timeline of speed changes, sorted by when they happen.
+type Schedule []SpeedChange
+
+// A SpeedChange changes a Writer's multiplier to Multiplier, At a time after
+// playing a Schedule starts.
+type SpeedChange struct {
+	At         time.Duration
+	Multiplier float64
+}
+
+// ParseSchedule reads a Schedule. Every change is a time offset and a
+// multiplier, like "10s 0.25x", on a line of its own or separated from other
+// changes with a slash. Blank lines and anything after a # are ignored.
+//
+//	0s 1x / 10s 0.25x / 12s 4x
+func ParseSchedule(r io.Reader) (Schedule, error) {
+	var s Schedule
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+
+		for _, change := range strings.Split(text, "/") {
+			fields := strings.Fields(change)
+			if len(fields) == 0 {
+				continue
+			}
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("line %d: expected a time and a multiplier: %q", line, strings.TrimSpace(change))
+			}
+
+			at, err := time.ParseDuration(fields[0])
+			if err != nil || at < 0 {
+				return nil, fmt.Errorf("line %d: invalid time: %s", line, fields[0])
+			}
+			m, err := strconv.ParseFloat(strings.TrimSuffix(fields[1], "x"), 64)
+			if err != nil || m <= 0 {
+				return nil, fmt.Errorf("line %d: invalid multiplier: %s", line, fields[1])
+			}
+			s = append(s, SpeedChange{At: at, Multiplier: m})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(s, func(i, j int) bool { return s[i].At < s[j].At })
+	return s, nil
+}
+
+// Play changes w's multiplier on schedule, starting now. Play returns once
+// every change has been made, or as soon as ctx is done. Time is kept by
+// clock, or by the system clock if clock is nil.
+func (s Schedule) Play(ctx context.Context, w interface{ SetMultiplier(m float64) }, clock Clock) error {
+	if clock == nil {
+		clock = realClock{}
+	}
+
+	start := clock.Now()
+	for _, change := range s {
+		if err := clock.Sleep(ctx, change.At-clock.Now().Sub(start)); err != nil {
+			return err
+		}
+		w.SetMultiplier(change.Multiplier)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package slow_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+	"github.com/blinsay/aslap/slow/slowtest"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  slow.Schedule
+		err   string
+	}{
+		{"nothing", "", nil, ""},
+		{"one change", "10s 0.25x", slow.Schedule{{10 * time.Second, 0.25}}, ""},
+		{"no x", "10s 2", slow.Schedule{{10 * time.Second, 2}}, ""},
+		{"slashes", "0s 1x / 10s 0.25x / 12s 4x", slow.Schedule{{0, 1}, {10 * time.Second, 0.25}, {12 * time.Second, 4}}, ""},
+		{"lines", "1s 2x\n\n  2s 3x  \n", slow.Schedule{{time.Second, 2}, {2 * time.Second, 3}}, ""},
+		{"comments", "# slow down\n1s 0.5x # a lot", slow.Schedule{{time.Second, 0.5}}, ""},
+		{"sorted", "2s 3x\n1s 2x", slow.Schedule{{time.Second, 2}, {2 * time.Second, 3}}, ""},
+		{"missing multiplier", "1s", nil, "line 1: expected a time and a multiplier"},
+		{"bad time", "\nsoon 2x", nil, "line 2: invalid time: soon"},
+		{"negative time", "-1s 2x", nil, "line 1: invalid time"},
+		{"bad multiplier", "1s fast", nil, "line 1: invalid multiplier: fast"},
+		{"zero multiplier", "1s 0x", nil, "line 1: invalid multiplier: 0x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := slow.ParseSchedule(strings.NewReader(tt.input))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("got error %v, want one about %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// remembers every multiplier it's set to
+type multipliers []float64
+
+func (m *multipliers) SetMultiplier(x float64) {
+	*m = append(*m, x)
+}
+
+func TestSchedulePlay(t *testing.T) {
+	s := slow.Schedule{{0, 1}, {10 * time.Second, 0.25}, {12 * time.Second, 4}}
+	clock := slowtest.NewClock(time.Unix(0, 0))
+
+	var got multipliers
+	if err := s.Play(context.Background(), &got, clock); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0] != 1 || got[1] != 0.25 || got[2] != 4 {
+		t.Errorf("set multipliers %v", got)
+	}
+	checkSleeps(t, clock.Sleeps(), []time.Duration{0, 10 * time.Second, 2 * time.Second})
+}
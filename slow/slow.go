@@ -0,0 +1,444 @@
+// Package slow makes output as slow as possible.
+//
+// A Writer wraps any io.Writer and waits a little while after every rune it
+// writes. How long it waits is up to its Patience. A Reader does the same for
+// any io.Reader, for when it's the reading side that should take its time.
+package slow
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// A Writer patiently writes to an underlying io.Writer, waiting after every
+// token. Unless it's told otherwise, every rune is a token.
+//
+// Tokens split across calls to Write are held until they're complete. Call
+// Close to write anything still held once there's nothing left to write.
+type Writer struct {
+	ctx       context.Context
+	sleepCtx  context.Context
+	skipSleep context.CancelFunc
+	skipping  int32
+	speed     uint64
+	clock     Clock
+	w         io.Writer
+	patience  Patience
+	tokenizer Tokenizer
+	perRune   bool
+	flush     func() error
+	onRune    func(r rune, delay time.Duration, pos int)
+	timeout   time.Duration
+	typos     *Typos
+	absolute  bool
+	fast      *regexp.Regexp
+	lines     bool
+	escapes   bool
+	buf       []byte
+	pos       Position
+
+	statsMu sync.Mutex
+	stats   Stats
+	start   time.Time
+	next    time.Time
+}
+
+// New returns a Writer that writes to w, configured by opts.
+func New(w io.Writer, opts ...Option) *Writer {
+	c := defaultConfig()
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	if c.patience == nil {
+		c.patience = BePatient(c.bits, c.base, c.step)
+	}
+	if c.flush == nil {
+		c.flush = makeFlush(w)
+	}
+
+	sw := &Writer{
+		clock:     c.clock,
+		w:         w,
+		patience:  c.patience,
+		tokenizer: c.tokenizer,
+		perRune:   c.perRune,
+		flush:     c.flush,
+		onRune:    c.onRune,
+		timeout:   c.timeout,
+		typos:     c.typos,
+		absolute:  c.absolute,
+		fast:      c.fast,
+		lines:     c.lines,
+		escapes:   c.escapes,
+	}
+	sw.pos.Prev = -1
+	sw.SetMultiplier(1)
+	if c.ctx == nil {
+		c.ctx = context.Background()
+	}
+	sw.setContext(c.ctx)
+	return sw
+}
+
+// waiting stops when ctx is done, or when someone Skips.
+func (w *Writer) setContext(ctx context.Context) {
+	w.ctx = ctx
+	w.sleepCtx, w.skipSleep = context.WithCancel(ctx)
+}
+
+// NewWriter returns a Writer that writes to w, waiting as long as p says
+// after every rune. It's shorthand for New(w, WithPatience(p)).
+func NewWriter(w io.Writer, p Patience) *Writer {
+	return New(w, WithPatience(p))
+}
+
+// SetClock sets the Clock the Writer uses to wait. Writers use the system
+// clock by default.
+func (w *Writer) SetClock(c Clock) {
+	w.clock = c
+}
+
+// Stats returns everything the Writer has done so far. It's safe to call from
+// any goroutine, even while a Write is waiting.
+func (w *Writer) Stats() Stats {
+	w.statsMu.Lock()
+	defer w.statsMu.Unlock()
+
+	return w.stats
+}
+
+// Skip stops being patient. Everything written after Skip is written
+// immediately, and any Write that's currently waiting stops waiting. Skip is
+// safe to call from any goroutine, and there's no going back.
+func (w *Writer) Skip() {
+	atomic.StoreInt32(&w.skipping, 1)
+	w.skipSleep()
+}
+
+func (w *Writer) skipped() bool {
+	return atomic.LoadInt32(&w.skipping) == 1
+}
+
+// SetMultiplier changes how fast the Writer goes. A Writer with a multiplier of
+// 2 waits half as long after every token as its Patience says to, and one with
+// a multiplier of 0.5 waits twice as long. Writers start with a multiplier of
+// 1.
+//
+// SetMultiplier is safe to call from any goroutine, and takes effect starting
+// with the next token. It panics if m isn't positive.
+func (w *Writer) SetMultiplier(m float64) {
+	if m <= 0 {
+		panic("multiplier must be positive")
+	}
+	atomic.StoreUint64(&w.speed, math.Float64bits(m))
+}
+
+// Multiplier returns how fast the Writer is currently going.
+func (w *Writer) Multiplier() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&w.speed))
+}
+
+// Write patiently writes p to the underlying writer. Write doesn't return
+// until every complete token in p has been written.
+//
+// If writing to the underlying writer fails, Write returns a WriteError. If
+// flushing it fails, Write returns a FlushError.
+func (w *Writer) Write(p []byte) (int, error) {
+	held := len(w.buf)
+	w.buf = append(w.buf, p...)
+
+	written, err := w.writeTokens(false)
+	if err != nil {
+		return clamp(written-held, 0, len(p)), err
+	}
+	return len(p), nil
+}
+
+// ReadFrom patiently writes everything read from r until EOF, without any
+// intermediate buffering. Like Write, anything at the end of r that isn't a
+// complete token is held until there's more to write or the Writer is closed.
+func (w *Writer) ReadFrom(r io.Reader) (int64, error) {
+	const minRead = 512
+
+	var n int64
+	for {
+		if cap(w.buf)-len(w.buf) < minRead {
+			buf := make([]byte, len(w.buf), 2*cap(w.buf)+minRead)
+			copy(buf, w.buf)
+			w.buf = buf
+		}
+
+		m, err := r.Read(w.buf[len(w.buf):cap(w.buf)])
+		w.buf = w.buf[:len(w.buf)+m]
+		n += int64(m)
+
+		if _, werr := w.writeTokens(false); werr != nil {
+			return n, werr
+		}
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, &ReadError{err}
+		}
+	}
+}
+
+// Close writes anything still held by the Writer. It does not close
+// the underlying writer.
+func (w *Writer) Close() error {
+	_, err := w.writeTokens(true)
+	return err
+}
+
+// write every token in the buffer, returning how many bytes of the buffer were
+// written. whatever isn't written stays in the buffer.
+func (w *Writer) writeTokens(atEOF bool) (int, error) {
+	written := 0
+	defer func() {
+		w.buf = w.buf[:copy(w.buf, w.buf[written:])]
+	}()
+
+	var m match
+	for written < len(w.buf) {
+		if w.lines && !atEOF && bytes.IndexByte(w.buf[written:], '\n') < 0 {
+			break
+		}
+
+		advance, token, fast, err := w.split(w.buf, written, atEOF, &m)
+		if err != nil {
+			return written, err
+		}
+		if advance == 0 {
+			break
+		}
+		if token == nil {
+			written += advance
+			continue
+		}
+
+		var delay time.Duration
+		if fast {
+			w.pass(token)
+		} else {
+			if err := w.maybeTypo(token); err != nil {
+				return written, err
+			}
+			delay = w.delay(token, w.buf[written+advance:])
+		}
+
+		err = w.emit(token, delay)
+		if _, unwritten := err.(*WriteError); !unwritten {
+			written += advance
+		}
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// where the next fast match is in a buffer, if there is one
+type match struct {
+	searched bool
+	loc      []int
+}
+
+// split the next token out of buf, starting at off. escape sequences and
+// anything matching the fast pattern are split out whole and are fast. a match that runs right up to
+// the end of buf might not be over yet, so it waits for more unless it's
+// atEOF.
+//
+// m remembers where the last match was, so the same buffer isn't searched over
+// and over again.
+func (w *Writer) split(buf []byte, off int, atEOF bool, m *match) (int, []byte, bool, error) {
+	if w.escapes && buf[off] == esc {
+		n, complete := escapeLen(buf[off:])
+		if !complete && !atEOF {
+			return 0, nil, false, nil
+		}
+		return n, buf[off : off+n], true, nil
+	}
+
+	if w.fast != nil {
+		if !m.searched || (m.loc != nil && m.loc[0] < off) {
+			m.searched, m.loc = true, nil
+			for from := off; from < len(buf); {
+				loc := w.fast.FindIndex(buf[from:])
+				if loc == nil {
+					break
+				}
+				if loc[1] > loc[0] {
+					m.loc = []int{from + loc[0], from + loc[1]}
+					break
+				}
+				// matching nothing doesn't count. look again a rune later.
+				_, size := utf8.DecodeRune(buf[from+loc[0]:])
+				from += loc[0] + size
+			}
+		}
+
+		if m.loc != nil {
+			if m.loc[0] == off {
+				if m.loc[1] == len(buf) && !atEOF {
+					return 0, nil, false, nil
+				}
+				return m.loc[1] - off, buf[off:m.loc[1]], true, nil
+			}
+			advance, token, err := w.tokenizer.Split(buf[off:m.loc[0]], true)
+			return advance, token, false, err
+		}
+	}
+
+	advance, token, err := w.tokenizer.Split(buf[off:], atEOF)
+	return advance, token, false, err
+}
+
+// write, flush, and then wait. whatever happens ends up in the stats.
+func (w *Writer) emit(token []byte, delay time.Duration) error {
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+	if w.stats.Tokens == 0 {
+		w.start = w.clock.Now()
+		w.next = w.start
+	}
+
+	if err := w.write(token); err != nil {
+		return &WriteError{err}
+	}
+	if err := w.flush(); err != nil {
+		return &FlushError{err}
+	}
+
+	// keeping absolute time means waiting until the next token is due, not
+	// just waiting. whatever time it took to write this one doesn't count.
+	sleep := delay
+	if w.absolute {
+		w.next = w.next.Add(delay)
+		sleep = w.next.Sub(w.clock.Now())
+		// falling behind means not waiting at all, not waiting less than that
+		if sleep < 0 {
+			sleep = 0
+		}
+	}
+
+	err := w.clock.Sleep(w.sleepCtx, sleep)
+	if err != nil {
+		sleep = 0
+	}
+	if err != nil && w.skipped() && w.ctx.Err() == nil {
+		err = nil
+	}
+
+	// the stats are about what actually happened, not what was planned
+	w.statsMu.Lock()
+	w.stats.record(utf8.RuneCount(token), len(token), sleep)
+	w.stats.Wall = w.clock.Now().Sub(w.start)
+	w.statsMu.Unlock()
+	return err
+}
+
+// write a token to the underlying writer, giving up if it takes too long.
+func (w *Writer) write(token []byte) error {
+	type deadliner interface{ SetWriteDeadline(time.Time) error }
+
+	d, ok := w.w.(deadliner)
+	if !ok || w.timeout <= 0 {
+		_, err := w.w.Write(token)
+		return err
+	}
+
+	if err := d.SetWriteDeadline(time.Now().Add(w.timeout)); err != nil {
+		return err
+	}
+	_, err := w.w.Write(token)
+	if derr := d.SetWriteDeadline(time.Time{}); err == nil {
+		err = derr
+	}
+	return err
+}
+
+// how long to wait after writing a token. anyone watching hears about every
+// rune in it, even the ones that aren't waited for.
+//
+// ahead is whatever's already known to come after the token.
+func (w *Writer) delay(token, ahead []byte) time.Duration {
+	skipped := w.skipped()
+	speed := w.Multiplier()
+
+	var total time.Duration
+	first := true
+	for len(token) > 0 {
+		// escapes in the middle of a token don't count
+		if w.escapes && token[0] == esc {
+			n, _ := escapeLen(token)
+			w.pass(token[:n])
+			token = token[n:]
+			continue
+		}
+
+		r, size := decodeRune(token)
+		token = token[size:]
+
+		var d time.Duration
+		if !skipped && (first || w.perRune) {
+			pos := w.pos
+			pos.Ahead = ahead
+			if len(token) > 0 {
+				pos.Ahead = append(token[:len(token):len(token)], ahead...)
+			}
+			d = time.Duration(float64(delayAt(w.patience, r, pos)) / speed)
+		}
+		if w.onRune != nil {
+			w.onRune(r, d, w.pos.Rune)
+		}
+
+		w.pos.advance(r, size)
+		total += d
+		first = false
+	}
+	return total
+}
+
+// let a token through without waiting for it. anyone watching still hears
+// about it.
+func (w *Writer) pass(token []byte) {
+	for len(token) > 0 {
+		r, size := decodeRune(token)
+		token = token[size:]
+
+		if w.onRune != nil {
+			w.onRune(r, 0, w.pos.Rune)
+		}
+		w.pos.advance(r, size)
+	}
+}
+
+// decode the first rune in p. a byte that isn't UTF-8 is its own rune, so
+// different bytes still get different delays.
+func decodeRune(p []byte) (rune, int) {
+	r, size := utf8.DecodeRune(p)
+	if r == utf8.RuneError && size == 1 {
+		r = rune(p[0])
+	}
+	return r, size
+}
+
+func clamp(n, lo, hi int) int {
+	if n < lo {
+		return lo
+	}
+	if n > hi {
+		return hi
+	}
+	return n
+}
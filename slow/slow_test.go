@@ -0,0 +1,426 @@
+package slow_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+	"github.com/blinsay/aslap/slow/slowtest"
+)
+
+// write every chunk to a Writer that never actually waits, and then close it.
+// returns everything that was written, and every wait along the way.
+func play(t *testing.T, chunks []string, opts ...slow.Option) (string, []time.Duration) {
+	t.Helper()
+
+	clock := slowtest.NewClock(time.Unix(0, 0))
+	var out bytes.Buffer
+	w := slow.New(&out, append([]slow.Option{slow.WithClock(clock)}, opts...)...)
+	for _, chunk := range chunks {
+		if _, err := io.WriteString(w, chunk); err != nil {
+			t.Fatalf("error writing %q: %s", chunk, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("error closing: %s", err)
+	}
+	return out.String(), clock.Sleeps()
+}
+
+func checkSleeps(t *testing.T, got, want []time.Duration) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("slept %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("slept %v, want %v", got, want)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	// by default, 1s plus 100ms for every unit of the low 3 bits
+	tests := []struct {
+		name   string
+		chunks []string
+		want   []time.Duration
+	}{
+		{"nothing", nil, nil},
+		{"empty", []string{""}, nil},
+		{"ascii", []string{"abc"}, []time.Duration{1100 * time.Millisecond, 1200 * time.Millisecond, 1300 * time.Millisecond}},
+		{"newline", []string{"h\n"}, []time.Duration{1 * time.Second, 1200 * time.Millisecond}},
+		{"multibyte", []string{"é世"}, []time.Duration{1100 * time.Millisecond, 1600 * time.Millisecond}},
+		{"split writes", []string{"a", "bc"}, []time.Duration{1100 * time.Millisecond, 1200 * time.Millisecond, 1300 * time.Millisecond}},
+		{"split rune", []string{"\xe4", "\xb8", "\x96"}, []time.Duration{1600 * time.Millisecond}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := strings.Join(tt.chunks, "")
+			got, sleeps := play(t, tt.chunks)
+			if got != input {
+				t.Errorf("wrote %q, want %q", got, input)
+			}
+			checkSleeps(t, sleeps, tt.want)
+		})
+	}
+}
+
+func TestWriterHoldsIncompleteRunes(t *testing.T) {
+	clock := slowtest.NewClock(time.Unix(0, 0))
+	var out bytes.Buffer
+	w := slow.New(&out, slow.WithClock(clock))
+
+	n, err := w.Write([]byte("a\xe4\xb8"))
+	if err != nil || n != 3 {
+		t.Fatalf("Write returned %d, %v, want 3, nil", n, err)
+	}
+	if out.String() != "a" {
+		t.Fatalf("wrote %q before the rune was done, want %q", out.String(), "a")
+	}
+
+	if _, err := w.Write([]byte("\x96")); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "a世" {
+		t.Fatalf("wrote %q, want %q", out.String(), "a世")
+	}
+	checkSleeps(t, clock.Sleeps(), []time.Duration{1100 * time.Millisecond, 1600 * time.Millisecond})
+}
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []slow.Option
+		want []time.Duration
+	}{
+		{"defaults", nil, []time.Duration{1100 * time.Millisecond, 1200 * time.Millisecond}},
+		{"base delay", []slow.Option{slow.WithBaseDelay(0)}, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}},
+		{"step", []slow.Option{slow.WithStep(time.Millisecond)}, []time.Duration{1001 * time.Millisecond, 1002 * time.Millisecond}},
+		{"bits", []slow.Option{slow.WithBits(0)}, []time.Duration{time.Second, time.Second}},
+		{"patience", []slow.Option{slow.WithPatience(codePoints)}, []time.Duration{97 * time.Millisecond, 98 * time.Millisecond}},
+		// patience wins over everything else
+		{"patience and base delay", []slow.Option{slow.WithBaseDelay(0), slow.WithPatience(codePoints)}, []time.Duration{97 * time.Millisecond, 98 * time.Millisecond}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, sleeps := play(t, []string{"ab"}, tt.opts...)
+			checkSleeps(t, sleeps, tt.want)
+		})
+	}
+}
+
+// a writer that takes a while to write anything, on clock's time
+type sluggish struct {
+	clock *slowtest.Clock
+	take  time.Duration
+}
+
+func (s sluggish) Write(p []byte) (int, error) {
+	s.clock.Sleep(context.Background(), s.take)
+	return len(p), nil
+}
+
+func TestAbsoluteTime(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name     string
+		absolute bool
+		take     time.Duration
+		// every write, and then every wait after it
+		want []time.Duration
+	}{
+		{"relative", false, 30 * ms, []time.Duration{30 * ms, 100 * ms, 30 * ms, 100 * ms, 30 * ms, 100 * ms}},
+		{"absolute", true, 30 * ms, []time.Duration{30 * ms, 70 * ms, 30 * ms, 70 * ms, 30 * ms, 70 * ms}},
+		// falling behind means not waiting at all until it's caught up
+		{"behind", true, 150 * ms, []time.Duration{150 * ms, 0, 150 * ms, 0, 150 * ms, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := slowtest.NewClock(time.Unix(0, 0))
+			opts := []slow.Option{slow.WithClock(clock), slow.WithPatience(always(100 * ms))}
+			if tt.absolute {
+				opts = append(opts, slow.WithAbsoluteTime())
+			}
+			w := slow.New(sluggish{clock, tt.take}, opts...)
+			if _, err := io.WriteString(w, "abc"); err != nil {
+				t.Fatal(err)
+			}
+			checkSleeps(t, clock.Sleeps(), tt.want)
+
+			// only the waiting counts as having slept
+			var slept time.Duration
+			for i := 1; i < len(tt.want); i += 2 {
+				slept += tt.want[i]
+			}
+			if got := w.Stats().Slept; got != slept {
+				t.Errorf("stats say it slept %v, want %v", got, slept)
+			}
+		})
+	}
+}
+
+func TestFastMatch(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name   string
+		re     string
+		chunks []string
+		want   []time.Duration
+	}{
+		{"no match", `\d+`, []string{"ab"}, []time.Duration{97 * ms, 98 * ms}},
+		{"match", `\d+`, []string{"a12b"}, []time.Duration{97 * ms, 0, 98 * ms}},
+		{"match at the end", `\d+`, []string{"a12"}, []time.Duration{97 * ms, 0}},
+		// a match that might keep going waits to see whether it does
+		{"match across writes", `\d+`, []string{"a1", "2b"}, []time.Duration{97 * ms, 0, 98 * ms}},
+		{"empty matches", `x*`, []string{"ab"}, []time.Duration{97 * ms, 98 * ms}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, sleeps := play(t, tt.chunks, slow.WithPatience(codePoints), slow.WithFastMatch(regexp.MustCompile(tt.re)))
+			if want := strings.Join(tt.chunks, ""); got != want {
+				t.Errorf("wrote %q, want %q", got, want)
+			}
+			checkSleeps(t, sleeps, tt.want)
+		})
+	}
+}
+
+// a clock that waits until it's told to stop, and says when it starts
+type stuck struct {
+	*slowtest.Clock
+	waiting chan struct{}
+}
+
+func (s stuck) Sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case s.waiting <- struct{}{}:
+	default:
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestSkipWhileWaiting(t *testing.T) {
+	clock := stuck{slowtest.NewClock(time.Unix(0, 0)), make(chan struct{})}
+	var out bytes.Buffer
+	w := slow.New(&out, slow.WithClock(clock))
+
+	done := make(chan error)
+	go func() {
+		_, err := io.WriteString(w, "ab")
+		done <- err
+	}()
+
+	<-clock.waiting
+	w.Skip()
+	if err := <-done; err != nil {
+		t.Fatalf("Write returned %v after skipping, want nil", err)
+	}
+	if out.String() != "ab" {
+		t.Errorf("wrote %q, want %q", out.String(), "ab")
+	}
+}
+
+func TestSkip(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		// every wait before skipping
+		want []time.Duration
+	}{
+		{"right away", "", "abc", nil},
+		{"partway", "ab", "cd", []time.Duration{97 * ms, 98 * ms}},
+		{"at the end", "ab", "", []time.Duration{97 * ms, 98 * ms}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := slowtest.NewClock(time.Unix(0, 0))
+			var out bytes.Buffer
+			w := slow.New(&out, slow.WithClock(clock), slow.WithPatience(codePoints))
+
+			if _, err := io.WriteString(w, tt.before); err != nil {
+				t.Fatal(err)
+			}
+			w.Skip()
+			if _, err := io.WriteString(w, tt.after); err != nil {
+				t.Fatalf("error writing after skipping: %s", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if want := tt.before + tt.after; out.String() != want {
+				t.Errorf("wrote %q, want %q", out.String(), want)
+			}
+			checkSleeps(t, clock.Sleeps(), tt.want)
+
+			// everything after skipping counts, but none of it was waited for
+			var slept time.Duration
+			for _, d := range tt.want {
+				slept += d
+			}
+			stats := w.Stats()
+			if stats.Runes != int64(len(tt.before+tt.after)) || stats.Slept != slept {
+				t.Errorf("stats say %d runes in %v, want %d in %v", stats.Runes, stats.Slept, len(tt.before+tt.after), slept)
+			}
+			if tt.after != "" && stats.MinDelay != 0 {
+				t.Errorf("stats say the shortest wait was %v, want 0", stats.MinDelay)
+			}
+		})
+	}
+}
+
+// a writer with deadlines, like a net.Conn. every write fails with fail, and
+// every deadline it's given is remembered.
+type deadliner struct {
+	deadlines []time.Time
+	fail      error
+	failSet   error
+}
+
+func (d *deadliner) Write(p []byte) (int, error) {
+	if d.fail != nil {
+		return 0, d.fail
+	}
+	return len(p), nil
+}
+
+func (d *deadliner) SetWriteDeadline(t time.Time) error {
+	d.deadlines = append(d.deadlines, t)
+	return d.failSet
+}
+
+func TestWriteTimeout(t *testing.T) {
+	refused := errors.New("refused")
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		fail    error
+		failSet error
+		// how many deadlines get set, counting clearing them
+		deadlines int
+		timedOut  bool
+	}{
+		{"no timeout", 0, nil, nil, 0, false},
+		// every write gets a deadline, and then it's cleared again
+		{"timeout", time.Minute, nil, nil, 4, false},
+		{"timed out", time.Minute, os.ErrDeadlineExceeded, nil, 2, true},
+		{"failed", time.Minute, refused, nil, 2, false},
+		{"no deadline", time.Minute, nil, refused, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &deadliner{fail: tt.fail, failSet: tt.failSet}
+			w := slow.New(d, slow.WithClock(slowtest.NewClock(time.Unix(0, 0))), slow.WithWriteTimeout(tt.timeout))
+
+			before := time.Now()
+			_, err := io.WriteString(w, "ab")
+			after := time.Now()
+
+			if len(d.deadlines) != tt.deadlines {
+				t.Fatalf("set %d deadlines, want %d", len(d.deadlines), tt.deadlines)
+			}
+			for i, deadline := range d.deadlines {
+				if i%2 == 1 {
+					if !deadline.IsZero() {
+						t.Errorf("deadline %d is %v, want it cleared", i, deadline)
+					}
+					continue
+				}
+				if deadline.Before(before.Add(tt.timeout)) || deadline.After(after.Add(tt.timeout)) {
+					t.Errorf("deadline %d is %v, want %v from when it was written", i, deadline, tt.timeout)
+				}
+			}
+
+			want := tt.fail
+			if want == nil {
+				want = tt.failSet
+			}
+			if want == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			var werr *slow.WriteError
+			if !errors.As(err, &werr) || !errors.Is(err, want) {
+				t.Fatalf("got error %v, want a WriteError wrapping %v", err, want)
+			}
+			if werr.Timeout() != tt.timedOut {
+				t.Errorf("Timeout() = %v, want %v", werr.Timeout(), tt.timedOut)
+			}
+		})
+	}
+}
+
+// a reader that returns exactly one chunk for every Read
+type chunked []string
+
+func (c *chunked) Read(p []byte) (int, error) {
+	if len(*c) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, (*c)[0])
+	(*c)[0] = (*c)[0][n:]
+	if (*c)[0] == "" {
+		*c = (*c)[1:]
+	}
+	return n, nil
+}
+
+func TestReadFrom(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name   string
+		chunks []string
+		opts   []slow.Option
+		want   []time.Duration
+	}{
+		{"nothing", nil, nil, nil},
+		{"one read", []string{"ab"}, []slow.Option{slow.WithPatience(codePoints)}, []time.Duration{97 * ms, 98 * ms}},
+		// half a rune waits for the rest of it
+		{"split rune", []string{"a\xe4", "\xb8", "\x96"}, []slow.Option{slow.WithPatience(codePoints)}, []time.Duration{97 * ms, 0x4e16 * ms}},
+		{"split word", []string{"he", "llo wo", "rld"}, []slow.Option{slow.WithPatience(always(ms)), slow.WithTokenizer(slow.Words)}, []time.Duration{6 * ms, 5 * ms}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := slowtest.NewClock(time.Unix(0, 0))
+			var out bytes.Buffer
+			w := slow.New(&out, append([]slow.Option{slow.WithClock(clock)}, tt.opts...)...)
+
+			input := strings.Join(tt.chunks, "")
+			src := chunked(append([]string(nil), tt.chunks...))
+			n, err := w.ReadFrom(&src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != int64(len(input)) {
+				t.Errorf("ReadFrom says it read %d bytes, want %d", n, len(input))
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != input {
+				t.Errorf("wrote %q, want %q", out.String(), input)
+			}
+			checkSleeps(t, clock.Sleeps(), tt.want)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Package slowtest provides utilities for testing things that are as slow as
+// possible, without taking as long as possible.
+package slowtest
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// A Clock is a slow.Clock that never actually waits. Every Sleep moves the
+// Clock forward instantly and is recorded, so the schedule a Writer or Reader
+// would have followed can be checked after the fact.
+//
+// A Clock is safe to use from multiple goroutines.
+type Clock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+// NewClock returns a Clock that starts at now.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Now returns the Clock's current time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Sleep records d and moves the Clock forward by d, without waiting. If ctx is
+// already done, nothing is recorded and Sleep returns ctx.Err().
+func (c *Clock) Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sleeps = append(c.sleeps, d)
+	if d > 0 {
+		c.now = c.now.Add(d)
+	}
+	return nil
+}
+
+// Sleeps returns every duration passed to Sleep so far, in order.
+func (c *Clock) Sleeps() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]time.Duration(nil), c.sleeps...)
+}
+
+// Elapsed returns the total time the Clock has moved forward.
+func (c *Clock) Elapsed() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var total time.Duration
+	for _, d := range c.sleeps {
+		if d > 0 {
+			total += d
+		}
+	}
+	return total
+}
@@ -0,0 +1,42 @@
+package slowtest
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestClock(t *testing.T) {
+	start := time.Unix(0, 0)
+	c := NewClock(start)
+
+	for _, d := range []time.Duration{time.Second, 0, -time.Second, 2 * time.Second} {
+		if err := c.Sleep(context.Background(), d); err != nil {
+			t.Fatalf("Sleep(%v) = %v", d, err)
+		}
+	}
+
+	// waiting less than nothing doesn't go back in time
+	if got, want := c.Now(), start.Add(3*time.Second); !got.Equal(want) {
+		t.Errorf("Now() = %v, want %v", got, want)
+	}
+	if got, want := c.Elapsed(), 3*time.Second; got != want {
+		t.Errorf("Elapsed() = %v, want %v", got, want)
+	}
+	if got := c.Sleeps(); len(got) != 4 || got[2] != -time.Second {
+		t.Errorf("Sleeps() = %v, want every sleep in order", got)
+	}
+}
+
+func TestClockCanceled(t *testing.T) {
+	c := NewClock(time.Unix(0, 0))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := c.Sleep(ctx, time.Second); err != context.Canceled {
+		t.Errorf("Sleep = %v, want %v", err, context.Canceled)
+	}
+	if len(c.Sleeps()) != 0 || c.Elapsed() != 0 {
+		t.Errorf("a canceled Sleep was recorded: %v", c.Sleeps())
+	}
+}
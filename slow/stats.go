@@ -0,0 +1,38 @@
+package slow
+
+import "time"
+
+// Stats describe what a Writer actually did.
+type Stats struct {
+	// Runes and Bytes count everything written to the underlying writer.
+	Runes int64
+	Bytes int64
+	// Tokens counts the number of writes to the underlying writer.
+	Tokens int64
+
+	// Slept is the total amount of time spent waiting, and Wall is the total
+	// time between starting the first write and finishing waiting after the
+	// last one.
+	Slept time.Duration
+	Wall  time.Duration
+
+	// MinDelay and MaxDelay are the shortest and longest waits after any one
+	// token.
+	MinDelay time.Duration
+	MaxDelay time.Duration
+}
+
+// record a token being written and waited on
+func (s *Stats) record(runes, bytes int, delay time.Duration) {
+	if s.Tokens == 0 || delay < s.MinDelay {
+		s.MinDelay = delay
+	}
+	if s.Tokens == 0 || delay > s.MaxDelay {
+		s.MaxDelay = delay
+	}
+
+	s.Tokens++
+	s.Runes += int64(runes)
+	s.Bytes += int64(bytes)
+	s.Slept += delay
+}
@@ -0,0 +1,65 @@
+package slow
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Stream returns Patience that lets someone else decide how long to wait. Every
+// time it's asked, it reads the next line from r and waits exactly as long as
+// it says, which means waiting for that line to show up in the first place.
+//
+// Lines are durations like "150ms", or plain numbers of seconds like "0.15".
+// Blank lines are skipped. Once r runs out, or a line doesn't make any sense,
+// Stream is as patient as fallback instead. If fallback is nil, it stops
+// waiting altogether.
+func Stream(r io.Reader, fallback Patience) Patience {
+	s := &stream{lines: bufio.NewScanner(r), fallback: fallback}
+	return PositionFunc(s.delay)
+}
+
+type stream struct {
+	mu       sync.Mutex
+	lines    *bufio.Scanner
+	done     bool
+	fallback Patience
+}
+
+func (s *stream) delay(r rune, pos Position) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for !s.done && s.lines.Scan() {
+		line := strings.TrimSpace(s.lines.Text())
+		if line == "" {
+			continue
+		}
+		if d, ok := parseDelay(line); ok {
+			return d
+		}
+		break
+	}
+
+	// once the stream stops making sense, it's done for good
+	s.done = true
+	if s.fallback == nil {
+		return 0
+	}
+	return delayAt(s.fallback, r, pos)
+}
+
+// a duration or a number of seconds. nothing negative.
+func parseDelay(s string) (time.Duration, bool) {
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs * float64(time.Second)), true
+	}
+	d, err := time.ParseDuration(s)
+	return d, err == nil && d >= 0
+}
@@ -0,0 +1,35 @@
+package slow_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestStream(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name     string
+		delays   string
+		fallback slow.Patience
+		want     []time.Duration
+	}{
+		{"durations", "150ms\n1s\n0s\n", nil, []time.Duration{150 * ms, time.Second, 0}},
+		{"seconds", "0.15\n2\n0\n", nil, []time.Duration{150 * ms, 2 * time.Second, 0}},
+		{"blank lines", "\n  10ms  \n\n20ms\n30ms", nil, []time.Duration{10 * ms, 20 * ms, 30 * ms}},
+		{"runs out", "10ms\n", always(time.Second), []time.Duration{10 * ms, time.Second, time.Second}},
+		{"runs out without a fallback", "10ms", nil, []time.Duration{10 * ms, 0, 0}},
+		// once it stops making sense, it's done, even if it makes sense again
+		{"nonsense", "10ms\nsoon\n20ms\n", always(time.Second), []time.Duration{10 * ms, time.Second, time.Second}},
+		{"negative", "-1s\n20ms\n", always(time.Second), []time.Duration{time.Second, time.Second, time.Second}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := slow.Stream(strings.NewReader(tt.delays), tt.fallback)
+			checkSleeps(t, waits(t, p, "abc"), tt.want)
+		})
+	}
+}
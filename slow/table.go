@@ -0,0 +1,166 @@
+package slow
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// A DelayTable says exactly how long to wait for particular runes, or ranges of
+// runes.
+type DelayTable struct {
+	// Default is how long to wait for anything not in the table, if there's a
+	// default at all.
+	Default *time.Duration
+
+	runes  map[rune]time.Duration
+	ranges []runeRange
+}
+
+type runeRange struct {
+	lo, hi rune
+	delay  time.Duration
+}
+
+// ParseDelayTable reads a DelayTable from JSON. Every key in "runes" is a
+// single character, a range of characters like "a-z", or code points and
+// ranges of code points like "U+00E9" or "U+0400-U+04FF". Every delay is a
+// Go duration.
+//
+//	{
+//	  "default": "100ms",
+//	  "runes": {
+//	    ".": "1s",
+//	    "a-z": "50ms",
+//	    "U+0400-U+04FF": "200ms"
+//	  }
+//	}
+//
+// When ranges overlap, the narrowest range wins. Single characters always win
+// over ranges.
+func ParseDelayTable(r io.Reader) (*DelayTable, error) {
+	var raw struct {
+		Default string            `json:"default"`
+		Runes   map[string]string `json:"runes"`
+	}
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	t := &DelayTable{runes: make(map[rune]time.Duration)}
+	if raw.Default != "" {
+		d, err := time.ParseDuration(raw.Default)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default: %s", err)
+		}
+		t.Default = &d
+	}
+
+	for key, value := range raw.Runes {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid delay for %q: %s", key, err)
+		}
+		lo, hi, err := parseRuneRange(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if lo == hi {
+			t.runes[lo] = d
+		} else {
+			t.ranges = append(t.ranges, runeRange{lo: lo, hi: hi, delay: d})
+		}
+	}
+
+	sort.Slice(t.ranges, func(i, j int) bool {
+		return t.ranges[i].hi-t.ranges[i].lo < t.ranges[j].hi-t.ranges[j].lo
+	})
+	return t, nil
+}
+
+// parse "a", "a-z", "U+0061", or "U+0061-U+007A"
+func parseRuneRange(key string) (rune, rune, error) {
+	if utf8.RuneCountInString(key) == 1 {
+		r, _ := utf8.DecodeRuneInString(key)
+		return r, r, nil
+	}
+
+	if rs := []rune(key); len(rs) == 3 && rs[1] == '-' {
+		if rs[0] > rs[2] {
+			return 0, 0, fmt.Errorf("invalid range: %q", key)
+		}
+		return rs[0], rs[2], nil
+	}
+
+	parts := strings.SplitN(key, "-", 2)
+	lo, err := parseCodePoint(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid rune: %q", key)
+	}
+	hi := lo
+	if len(parts) == 2 {
+		if hi, err = parseCodePoint(parts[1]); err != nil || lo > hi {
+			return 0, 0, fmt.Errorf("invalid range: %q", key)
+		}
+	}
+	return lo, hi, nil
+}
+
+func parseCodePoint(s string) (rune, error) {
+	if !strings.HasPrefix(s, "U+") {
+		return 0, fmt.Errorf("not a code point: %s", s)
+	}
+	n, err := strconv.ParseUint(s[2:], 16, 32)
+	if err != nil || n > utf8.MaxRune {
+		return 0, fmt.Errorf("not a code point: %s", s)
+	}
+	return rune(n), nil
+}
+
+// lookup how long to wait for r, if the table knows.
+func (t *DelayTable) lookup(r rune) (time.Duration, bool) {
+	if d, ok := t.runes[r]; ok {
+		return d, true
+	}
+	for _, rr := range t.ranges {
+		if rr.lo <= r && r <= rr.hi {
+			return rr.delay, true
+		}
+	}
+	if t.Default != nil {
+		return *t.Default, true
+	}
+	return 0, false
+}
+
+// Table returns Patience that waits as long as t says to, and as long as p
+// does for anything t doesn't know about.
+func Table(p Patience, t *DelayTable) Patience {
+	return &table{p: p, t: t}
+}
+
+type table struct {
+	p Patience
+	t *DelayTable
+}
+
+func (t *table) Delay(r rune) time.Duration {
+	return t.DelayAt(r, Position{Prev: -1})
+}
+
+func (t *table) DelayAt(r rune, pos Position) time.Duration {
+	if d, ok := t.t.lookup(r); ok {
+		return d
+	}
+	return delayAt(t.p, r, pos)
+}
+
+func (t *table) Reset() {
+	reset(t.p)
+}
@@ -0,0 +1,77 @@
+package slow_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestParseDelayTable(t *testing.T) {
+	ms := time.Millisecond
+	const example = `{
+		"default": "100ms",
+		"runes": {
+			".": "1s",
+			"a-z": "50ms",
+			"a-c": "20ms",
+			"U+00E9": "300ms",
+			"U+0400-U+04FF": "200ms"
+		}
+	}`
+
+	tests := []struct {
+		name  string
+		input string
+		r     rune
+		want  time.Duration
+	}{
+		{"single", example, '.', time.Second},
+		{"range", example, 'q', 50 * ms},
+		{"narrowest range", example, 'b', 20 * ms},
+		{"code point", example, 'é', 300 * ms},
+		{"code point range", example, 'Ж', 200 * ms},
+		{"default", example, '!', 100 * ms},
+		// without a default, whatever Patience is underneath gets a say
+		{"no default", `{"runes": {"a": "1s"}}`, 'b', ms},
+		{"single wins", `{"runes": {"a-z": "1s", "m": "2s"}}`, 'm', 2 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := slow.ParseDelayTable(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := slow.Table(always(ms), table).Delay(tt.r); got != tt.want {
+				t.Errorf("Delay(%q) = %v, want %v", tt.r, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDelayTableErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{"not json", `runes`, "invalid character"},
+		{"bad default", `{"default": "soon"}`, "invalid default"},
+		{"bad delay", `{"runes": {"a": "soon"}}`, `invalid delay for "a"`},
+		{"backwards range", `{"runes": {"z-a": "1s"}}`, `invalid range: "z-a"`},
+		{"backwards code points", `{"runes": {"U+0100-U+0001": "1s"}}`, "invalid range"},
+		{"not a rune", `{"runes": {"ab": "1s"}}`, `invalid rune: "ab"`},
+		{"too big", `{"runes": {"U+110000": "1s"}}`, "invalid rune"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := slow.ParseDelayTable(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got error %v, want one about %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package slow
+
+import (
+	"bytes"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// Timestamps returns Patience that replays lines at the same pace they were
+// originally written, according to the timestamps they start with. After the
+// end of every line, it waits as long as it was between that line's timestamp
+// and the next one's, divided by speed. Anything else waits as long as p says.
+//
+// Lines without timestamps, and lines followed by something that hasn't been
+// written yet, wait as long as p says after they end too. Time never goes
+// backwards, so a line that claims to be older than the one before it comes
+// right away.
+//
+// parse reads the timestamp at the start of a line, if there is one. If parse
+// is nil, Timestamps uses ParseTimestamp.
+func Timestamps(p Patience, speed float64, parse func(line []byte) (time.Time, bool)) Patience {
+	if parse == nil {
+		parse = ParseTimestamp
+	}
+	return &timestamps{p: p, speed: speed, parse: parse}
+}
+
+type timestamps struct {
+	p     Patience
+	speed float64
+	parse func([]byte) (time.Time, bool)
+
+	last  time.Time
+	known bool
+}
+
+func (t *timestamps) Delay(r rune) time.Duration {
+	return t.DelayAt(r, Position{Prev: -1})
+}
+
+func (t *timestamps) DelayAt(r rune, pos Position) time.Duration {
+	// remember when this line says it happened, so the newline at its end can
+	// wait until the next line's time
+	if pos.Column == 0 && r != '\n' {
+		line := append(encodeRune(r), firstLine(pos.Ahead)...)
+		t.last, t.known = t.parse(line)
+	}
+	if r != '\n' || !t.known {
+		return delayAt(t.p, r, pos)
+	}
+
+	next, ok := t.parse(firstLine(pos.Ahead))
+	if !ok {
+		return delayAt(t.p, r, pos)
+	}
+	if next.Before(t.last) {
+		return 0
+	}
+	return time.Duration(float64(next.Sub(t.last)) / t.speed)
+}
+
+func (t *timestamps) Reset() {
+	t.known = false
+	reset(t.p)
+}
+
+func firstLine(p []byte) []byte {
+	if i := bytes.IndexByte(p, '\n'); i >= 0 {
+		return p[:i]
+	}
+	return p
+}
+
+// the formats ParseTimestamp knows, longest first so fractional seconds aren't
+// left behind
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006/01/02 15:04:05.999999999",
+	"2006/01/02 15:04:05",
+	time.StampNano,
+	time.StampMicro,
+	time.StampMilli,
+	time.Stamp,
+}
+
+// ParseTimestamp reads the timestamp at the start of line, optionally in
+// square brackets. It knows RFC 3339 timestamps, the same with a space instead
+// of a T, the timestamps the log package writes, syslog timestamps like
+// "Jan  2 15:04:05", and Unix epoch times in seconds, milliseconds,
+// microseconds, or nanoseconds.
+//
+// Timestamps without a date or a time zone are all in UTC, which doesn't
+// matter when all that matters is the time between them.
+func ParseTimestamp(line []byte) (time.Time, bool) {
+	line = bytes.TrimPrefix(line, []byte("["))
+	s := string(line[:clamp(len(line), 0, len(time.RFC3339Nano)+2)])
+
+	for _, layout := range timestampLayouts {
+		// the timestamp is some prefix of the line, and no timestamp is
+		// shorter than a syslog one
+		for n := len(s); n >= len(time.Stamp); n-- {
+			if t, err := time.Parse(layout, s[:n]); err == nil {
+				return t, true
+			}
+		}
+	}
+	return parseEpoch(line)
+}
+
+// a number of seconds, milliseconds, microseconds, or nanoseconds since the
+// epoch. whichever makes it sometime after 2001.
+func parseEpoch(line []byte) (time.Time, bool) {
+	end := 0
+	for end < len(line) && (line[end] == '.' || '0' <= line[end] && line[end] <= '9') {
+		end++
+	}
+	if end < len(line) {
+		if r, _ := utf8.DecodeRune(line[end:]); r != ' ' && r != '\t' && r != ']' {
+			return time.Time{}, false
+		}
+	}
+
+	n, err := strconv.ParseFloat(string(line[:end]), 64)
+	if err != nil || n < 1e9 {
+		return time.Time{}, false
+	}
+	for n >= 1e11 {
+		n /= 1000
+	}
+	sec, frac := int64(n), n-float64(int64(n))
+	return time.Unix(sec, int64(frac*1e9)).UTC(), true
+}
@@ -0,0 +1,90 @@
+package slow_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want time.Time
+		ok   bool
+	}{
+		{"rfc 3339", "2020-01-02T03:04:05Z hello", time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), true},
+		{"rfc 3339 nano", "2020-01-02T03:04:05.25Z hello", time.Date(2020, 1, 2, 3, 4, 5, 250e6, time.UTC), true},
+		{"space", "2020-01-02 03:04:05 hello", time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), true},
+		{"space fractional", "2020-01-02 03:04:05.5 hello", time.Date(2020, 1, 2, 3, 4, 5, 500e6, time.UTC), true},
+		{"log", "2020/01/02 03:04:05 hello", time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), true},
+		{"brackets", "[2020-01-02T03:04:05Z] hello", time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), true},
+		{"syslog", "Jan  2 03:04:05 host hello", time.Date(0, 1, 2, 3, 4, 5, 0, time.UTC), true},
+		{"epoch seconds", "1600000000 hello", time.Unix(1600000000, 0).UTC(), true},
+		{"epoch fractional", "1600000000.5 hello", time.Unix(1600000000, 500e6).UTC(), true},
+		{"epoch millis", "1600000000000 hello", time.Unix(1600000000, 0).UTC(), true},
+		{"epoch nanos", "[1600000000000000000] hello", time.Unix(1600000000, 0).UTC(), true},
+		{"too small", "12345 hello", time.Time{}, false},
+		{"not a number", "1600000000x hello", time.Time{}, false},
+		{"nothing", "hello", time.Time{}, false},
+		{"empty", "", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := slow.ParseTimestamp([]byte(tt.line))
+			if ok != tt.ok || !got.Equal(tt.want) {
+				t.Errorf("ParseTimestamp(%q) = %v, %v, want %v, %v", tt.line, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestTimestamps(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name   string
+		speed  float64
+		chunks []string
+		want   []time.Duration
+	}{
+		{"no timestamps", 1, []string{"a\nb\n"}, []time.Duration{ms, ms, ms, ms}},
+		// everything but the newline waits as long as it usually would
+		{"replay", 1, []string{"1600000000 a\n1600000002 b\n"}, append(append(waiting(12, ms), 2*time.Second), waiting(13, ms)...)},
+		{"faster", 4, []string{"1600000000 a\n1600000002 b\n"}, append(append(waiting(12, ms), 500*ms), waiting(13, ms)...)},
+		{"backwards", 1, []string{"1600000002 a\n1600000000 b\n"}, append(append(waiting(12, ms), 0), waiting(13, ms)...)},
+		{"next line without one", 1, []string{"1600000000 a\nb\n"}, waiting(15, ms)},
+		// the next line hasn't been written yet when the newline is due
+		{"next line later", 1, []string{"1600000000 a\n", "1600000002 b\n"}, waiting(26, ms)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, sleeps := play(t, tt.chunks, slow.WithPatience(slow.Timestamps(always(ms), tt.speed, nil)))
+			checkSleeps(t, sleeps, tt.want)
+		})
+	}
+}
+
+func TestTimestampsParse(t *testing.T) {
+	// lines that start with how many seconds in they are
+	seconds := func(line []byte) (time.Time, bool) {
+		if len(line) == 0 || line[0] < '0' || line[0] > '9' {
+			return time.Time{}, false
+		}
+		return time.Unix(int64(line[0]-'0'), 0), true
+	}
+	ms := time.Millisecond
+	_, sleeps := play(t, []string{"1\n3\n"}, slow.WithPatience(slow.Timestamps(always(ms), 1, seconds)))
+	checkSleeps(t, sleeps, []time.Duration{ms, 2 * time.Second, ms, ms})
+}
+
+// n waits of d
+func waiting(n int, d time.Duration) []time.Duration {
+	waits := make([]time.Duration, n)
+	for i := range waits {
+		waits[i] = d
+	}
+	return waits
+}
@@ -0,0 +1,121 @@
+package slow
+
+import (
+	"bufio"
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// A Tokenizer splits bytes into the tokens a Writer is patient about. Every
+// token is written all at once, and the Writer waits as long as it would for
+// all of the token's runes put together (see WithLengthScaling).
+//
+// Split has the same contract as a bufio.SplitFunc.
+type Tokenizer interface {
+	Split(data []byte, atEOF bool) (advance int, token []byte, err error)
+}
+
+// The TokenizerFunc type is an adapter that allows a bufio.SplitFunc to be
+// used as a Tokenizer.
+type TokenizerFunc bufio.SplitFunc
+
+// Split returns f(data, atEOF).
+func (f TokenizerFunc) Split(data []byte, atEOF bool) (int, []byte, error) {
+	return f(data, atEOF)
+}
+
+// Runes splits input into individual runes. Invalid UTF-8 is replaced with
+// U+FFFD, one byte at a time.
+var Runes Tokenizer = TokenizerFunc(bufio.ScanRunes)
+
+// RawRunes splits input into individual runes exactly like Runes, except that
+// invalid UTF-8 is passed through untouched, one byte at a time. It's safe to
+// use on anything, even binary data.
+var RawRunes Tokenizer = TokenizerFunc(scanRawRunes)
+
+// Bytes splits input into individual bytes, even in the middle of a rune. Every
+// byte gets its own delay. A byte that isn't a whole rune on its own is passed
+// to Patience as the rune with the same value, so every byte of a multi-byte
+// rune can wait a different amount of time.
+var Bytes Tokenizer = TokenizerFunc(bufio.ScanBytes)
+
+// Words splits input into words. Every word keeps the whitespace that follows
+// it, and the first word keeps any whitespace before it too, so nothing is
+// lost on the way through.
+var Words Tokenizer = TokenizerFunc(scanWords)
+
+// Lines splits input into lines. Every line keeps its newline.
+var Lines Tokenizer = TokenizerFunc(scanLines)
+
+// Graphemes splits input into user-perceived characters, the extended grapheme
+// clusters described by Unicode Standard Annex #29. Combining marks stay with
+// the rune they combine with, emoji joined into a single emoji stay joined,
+// and flags are never split in half. Bytes that aren't UTF-8 are written as
+// they are, one at a time.
+var Graphemes Tokenizer = TokenizerFunc(scanGraphemes)
+
+func scanRawRunes(data []byte, atEOF bool) (int, []byte, error) {
+	if len(data) == 0 || !utf8.FullRune(data) && !atEOF {
+		return 0, nil, nil
+	}
+	_, size := utf8.DecodeRune(data)
+	return size, data[:size], nil
+}
+
+func scanWords(data []byte, atEOF bool) (int, []byte, error) {
+	// skip any whitespace before the word, to the end of the word, and then to
+	// the end of the whitespace that follows it.
+	i, seenWord, afterWord := 0, false, false
+	for i < len(data) {
+		if !utf8.FullRune(data[i:]) && !atEOF {
+			return 0, nil, nil
+		}
+		r, size := utf8.DecodeRune(data[i:])
+		space := unicode.IsSpace(r)
+		if afterWord && !space {
+			return i, data[:i], nil
+		}
+		if space && seenWord {
+			afterWord = true
+		}
+		if !space {
+			seenWord = true
+		}
+		i += size
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+func scanLines(data []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, data[:i+1], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+func scanGraphemes(data []byte, atEOF bool) (int, []byte, error) {
+	if len(data) == 0 || !utf8.FullRune(data) && !atEOF {
+		return 0, nil, nil
+	}
+	// invalid utf-8 doesn't combine with anything, and goes through untouched
+	// a byte at a time, just like it does with RawRunes
+	if r, size := utf8.DecodeRune(data); r == utf8.RuneError && size == 1 {
+		return 1, data[:1], nil
+	}
+
+	cluster, rest, _, _ := uniseg.FirstGraphemeCluster(data, -1)
+	// can't know the cluster is done until the next one starts
+	if !atEOF && (len(rest) == 0 || !utf8.FullRune(rest)) {
+		return 0, nil, nil
+	}
+	return len(cluster), cluster, nil
+}
@@ -0,0 +1,114 @@
+package slow_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+// split everything into tokens, the way a Writer would once it's closed
+func tokens(t *testing.T, tokenizer slow.Tokenizer, input string) []string {
+	t.Helper()
+
+	var tokens []string
+	data := []byte(input)
+	for len(data) > 0 {
+		advance, token, err := tokenizer.Split(data, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if advance == 0 {
+			t.Fatalf("no progress splitting %q", data)
+		}
+		if token != nil {
+			tokens = append(tokens, string(token))
+		}
+		data = data[advance:]
+	}
+	return tokens
+}
+
+func TestTokenizers(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokenizer slow.Tokenizer
+		input     string
+		want      []string
+	}{
+		{"runes", slow.Runes, "aé世", []string{"a", "é", "世"}},
+		{"runes invalid", slow.Runes, "a\xff", []string{"a", "�"}},
+		{"words", slow.Words, "hi  there\n", []string{"hi  ", "there\n"}},
+		{"words leading space", slow.Words, " hi", []string{" hi"}},
+		{"words leading spaces", slow.Words, "\n  hi there", []string{"\n  hi ", "there"}},
+		{"lines", slow.Lines, "a\n\nb", []string{"a\n", "\n", "b"}},
+		{"graphemes", slow.Graphemes, "ab", []string{"a", "b"}},
+		{"graphemes combining", slow.Graphemes, "e\u0301x", []string{"e\u0301", "x"}},
+		{"graphemes flag", slow.Graphemes, "🇳🇿!", []string{"🇳🇿", "!"}},
+		{"graphemes joined", slow.Graphemes, "👩\u200d💻", []string{"👩\u200d💻"}},
+		{"raw runes", slow.RawRunes, "aé世", []string{"a", "é", "世"}},
+		{"raw runes invalid", slow.RawRunes, "a\xffb", []string{"a", "\xff", "b"}},
+		{"graphemes invalid", slow.Graphemes, "a\xffb", []string{"a", "\xff", "b"}},
+		{"bytes", slow.Bytes, "aé", []string{"a", "\xc3", "\xa9"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tokens(t, tt.tokenizer, tt.input)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("split %q into %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// nothing that might not be over yet gets split off until there's more
+func TestTokenizersWait(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokenizer slow.Tokenizer
+		input     string
+	}{
+		{"runes", slow.Runes, "\xe4\xb8"},
+		{"raw runes", slow.RawRunes, "\xe4\xb8"},
+		{"words", slow.Words, "hi"},
+		{"words and spaces", slow.Words, "hi  "},
+		{"words leading spaces", slow.Words, "  "},
+		{"lines", slow.Lines, "no newline"},
+		{"graphemes", slow.Graphemes, "e"},
+		{"graphemes combining", slow.Graphemes, "e\u0301"},
+		{"graphemes incomplete", slow.Graphemes, "e\xcc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			advance, token, err := tt.tokenizer.Split([]byte(tt.input), false)
+			if advance != 0 || token != nil || err != nil {
+				t.Errorf("Split(%q) = %d, %q, %v, want it to wait for more", tt.input, advance, token, err)
+			}
+		})
+	}
+}
+
+func TestWritingWords(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name  string
+		scale bool
+		want  []time.Duration
+	}{
+		{"scaled", true, []time.Duration{(104 + 105 + 32) * ms, (116 + 111) * ms}},
+		{"once per word", false, []time.Duration{104 * ms, 116 * ms}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, sleeps := play(t, []string{"hi to"}, slow.WithPatience(codePoints), slow.WithTokenizer(slow.Words), slow.WithLengthScaling(tt.scale))
+			if got != "hi to" {
+				t.Errorf("wrote %q", got)
+			}
+			checkSleeps(t, sleeps, tt.want)
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package slow
+
+import (
+	"math"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A Layout describes where every character is on a keyboard, and which finger
+// a touch typist would use to type it.
+//
+// Every Layout is laid out on the same physical keyboard, a US ANSI keyboard.
+// Layouts only differ in which character is printed on which key.
+type Layout struct {
+	keys map[rune]key
+}
+
+// a key on the keyboard
+type key struct {
+	x, y    float64
+	finger  int
+	shifted bool
+}
+
+// the fingers, in order from left pinky to right pinky.
+const (
+	leftPinky = iota
+	leftRing
+	leftMiddle
+	leftIndex
+	rightIndex
+	rightMiddle
+	rightRing
+	rightPinky
+)
+
+func (k key) hand() int {
+	if k.finger <= leftIndex {
+		return 0
+	}
+	return 1
+}
+
+var (
+	// how far every row is shifted to the right of the number row, in keys
+	rowOffsets = []float64{0, 1.5, 1.75, 2.25}
+
+	// which finger types every key on every row
+	rowFingers = [][]int{
+		{leftPinky, leftPinky, leftRing, leftMiddle, leftIndex, leftIndex, rightIndex, rightIndex, rightMiddle, rightRing, rightPinky, rightPinky, rightPinky},
+		{leftPinky, leftRing, leftMiddle, leftIndex, leftIndex, rightIndex, rightIndex, rightMiddle, rightRing, rightPinky, rightPinky, rightPinky, rightPinky},
+		{leftPinky, leftRing, leftMiddle, leftIndex, leftIndex, rightIndex, rightIndex, rightMiddle, rightRing, rightPinky, rightPinky},
+		{leftPinky, leftRing, leftMiddle, leftIndex, leftIndex, rightIndex, rightIndex, rightMiddle, rightRing, rightPinky},
+	}
+)
+
+// NewLayout returns a Layout from the characters printed on every row of the
+// keyboard, from the number row down. Every row is given as a pair of strings:
+// the characters typed without shift, and the characters typed with it.
+//
+// NewLayout panics if it's given a row that doesn't exist, or more keys on a
+// row than there are.
+func NewLayout(rows ...string) *Layout {
+	if len(rows)%2 != 0 || len(rows)/2 > len(rowFingers) {
+		panic("slow: wrong number of rows")
+	}
+
+	l := &Layout{keys: make(map[rune]key)}
+	for i := 0; i < len(rows); i += 2 {
+		y := i / 2
+		for shift, row := range rows[i : i+2] {
+			x := 0
+			for _, r := range row {
+				if x >= len(rowFingers[y]) {
+					panic("slow: too many keys on row")
+				}
+				l.keys[r] = key{
+					x:       float64(x) + rowOffsets[y],
+					y:       float64(y),
+					finger:  rowFingers[y][x],
+					shifted: shift == 1,
+				}
+				x++
+			}
+		}
+	}
+	return l
+}
+
+// Some common layouts.
+var (
+	QWERTY = NewLayout(
+		"`1234567890-=", "~!@#$%^&*()_+",
+		"qwertyuiop[]\\", "QWERTYUIOP{}|",
+		"asdfghjkl;'", "ASDFGHJKL:\"",
+		"zxcvbnm,./", "ZXCVBNM<>?",
+	)
+	Dvorak = NewLayout(
+		"`1234567890[]", "~!@#$%^&*(){}",
+		"',.pyfgcrl/=\\", "\"<>PYFGCRL?+|",
+		"aoeuidhtns-", "AOEUIDHTNS_",
+		";qjkxbmwvz", ":QJKXBMWVZ",
+	)
+	Colemak = NewLayout(
+		"`1234567890-=", "~!@#$%^&*()_+",
+		"qwfpgjluy;[]\\", "QWFPGJLUY:{}|",
+		"arstdhneio'", "ARSTDHNEIO\"",
+		"zxcvbkm,./", "ZXCVBKM<>?",
+	)
+)
+
+// Typist returns Patience that types like someone who touch types on layout,
+// taking about base to type every character. Alternating hands is quick,
+// reaching across the keyboard with one hand is slower, and typing two
+// different keys with the same finger is slowest of all. Anything that isn't
+// on the keyboard takes exactly base, and so does anything that isn't followed
+// by anything yet.
+func Typist(layout *Layout, base time.Duration) Patience {
+	return PositionFunc(func(r rune, pos Position) time.Duration {
+		// the wait after r is however long it takes to get to the next key
+		if len(pos.Ahead) == 0 {
+			return base
+		}
+		next, _ := utf8.DecodeRune(pos.Ahead)
+		return time.Duration(float64(base) * layout.effort(r, next))
+	})
+}
+
+// how much harder than usual it is to type next after prev
+func (l *Layout) effort(prev, next rune) float64 {
+	if next == ' ' {
+		// thumbs are always ready
+		return 0.7
+	}
+
+	to, ok := l.keys[next]
+	if !ok {
+		return 1
+	}
+	from, ok := l.keys[prev]
+	if !ok {
+		return 1
+	}
+
+	distance := math.Hypot(to.x-from.x, to.y-from.y)
+	switch {
+	case distance == 0:
+		return 0.8
+	case from.finger == to.finger:
+		return 1.4 + 0.3*distance
+	case from.hand() == to.hand():
+		return 1 + 0.15*distance
+	default:
+		return 0.6
+	}
+}
+
+// ShiftPenalty returns Patience that waits d longer before anything typed with
+// shift on layout, as long as it knows what's coming next. Uppercase letters
+// that aren't on layout at all need shift too. There's no penalty for
+// something shifted right after something else shifted. A nil layout is
+// QWERTY.
+func ShiftPenalty(p Patience, layout *Layout, d time.Duration) Patience {
+	if layout == nil {
+		layout = QWERTY
+	}
+	return &shiftPenalty{p: p, layout: layout, d: d}
+}
+
+type shiftPenalty struct {
+	p      Patience
+	layout *Layout
+	d      time.Duration
+}
+
+func (s *shiftPenalty) Delay(r rune) time.Duration {
+	return s.DelayAt(r, Position{Prev: -1})
+}
+
+func (s *shiftPenalty) DelayAt(r rune, pos Position) time.Duration {
+	d := delayAt(s.p, r, pos)
+	if len(pos.Ahead) > 0 {
+		next, _ := utf8.DecodeRune(pos.Ahead)
+		// anyone already holding shift down keeps holding it
+		if s.layout.shifted(next) && !s.layout.shifted(r) {
+			d += s.d
+		}
+	}
+	return d
+}
+
+func (s *shiftPenalty) Reset() {
+	reset(s.p)
+}
+
+// whether typing r takes the shift key
+func (l *Layout) shifted(r rune) bool {
+	if k, ok := l.keys[r]; ok {
+		return k.shifted
+	}
+	return unicode.IsUpper(r)
+}
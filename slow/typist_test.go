@@ -0,0 +1,80 @@
+package slow_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+// whether two delays are the same, give or take rounding
+func near(got, want time.Duration) bool {
+	diff := got - want
+	return diff > -time.Microsecond && diff < time.Microsecond
+}
+
+func TestTypist(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name   string
+		layout *slow.Layout
+		input  string
+		want   []time.Duration
+	}{
+		// the wait after a key is however long it takes to reach the next one,
+		// and there's nothing to reach for after the last one
+		{"alternating hands", slow.QWERTY, "fj", []time.Duration{60 * ms, 100 * ms}},
+		{"same key", slow.QWERTY, "ff", []time.Duration{80 * ms, 100 * ms}},
+		{"same hand", slow.QWERTY, "fd", []time.Duration{115 * ms, 100 * ms}},
+		// v is a row down and half a key over from f
+		{"same finger", slow.QWERTY, "fv", []time.Duration{time.Duration(float64(100*ms) * (1.4 + 0.3*math.Hypot(0.5, 1))), 100 * ms}},
+		// thumbs are always ready, but the space bar isn't anywhere near
+		// anything else
+		{"space", slow.QWERTY, "f j", []time.Duration{70 * ms, 100 * ms, 100 * ms}},
+		{"off the keyboard", slow.QWERTY, "féf", []time.Duration{100 * ms, 100 * ms, 100 * ms}},
+		// the same keys are different letters on other layouts
+		{"dvorak", slow.Dvorak, "uh", []time.Duration{60 * ms, 100 * ms}},
+		{"colemak", slow.Colemak, "tn", []time.Duration{60 * ms, 100 * ms}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, sleeps := play(t, []string{tt.input}, slow.WithPatience(slow.Typist(tt.layout, 100*ms)))
+			if len(sleeps) != len(tt.want) {
+				t.Fatalf("slept %v, want %v", sleeps, tt.want)
+			}
+			for i := range sleeps {
+				if !near(sleeps[i], tt.want[i]) {
+					t.Fatalf("slept %v, want %v", sleeps, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestShiftPenalty(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name   string
+		layout *slow.Layout
+		input  string
+		want   []time.Duration
+	}{
+		// reaching for shift happens before whatever needs it
+		{"uppercase", nil, "aB", []time.Duration{60 * ms, 10 * ms}},
+		{"symbol", nil, "a!", []time.Duration{60 * ms, 10 * ms}},
+		{"still holding shift", nil, "AB", []time.Duration{10 * ms, 10 * ms}},
+		{"no shift", nil, "ab", []time.Duration{10 * ms, 10 * ms}},
+		// anything uppercase needs shift, on the keyboard or not
+		{"off the layout", nil, "aÉ", []time.Duration{60 * ms, 10 * ms}},
+		{"other layouts", slow.Dvorak, "a<", []time.Duration{60 * ms, 10 * ms}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := slow.ShiftPenalty(always(10*ms), tt.layout, 50*ms)
+			checkSleeps(t, waits(t, p, tt.input), tt.want)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package slow
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Typos describe how often a Writer makes mistakes, and how it fixes them.
+//
+// A typo is a wrong character, written as if it were the right one, followed
+// by a pause to notice the mistake, something to erase it, and then the right
+// character. Wrong characters are usually a neighbor of the right one on the
+// keyboard.
+//
+// Typos only happen when a token is a single letter, so they're best used
+// when output is split into Runes or Graphemes.
+type Typos struct {
+	// Rate is the chance of any letter being a typo, from 0 to 1.
+	Rate float64
+	// Ramp is how many runes it takes to get that sloppy. If it's more than
+	// zero, the chance of a typo starts at nothing and grows to Rate over the
+	// first Ramp runes.
+	Ramp int
+	// Layout is the keyboard typos are made on. If it's nil, typos are made on
+	// a QWERTY keyboard.
+	Layout *Layout
+	// Notice is how long it takes to notice a mistake, on top of waiting for
+	// the wrong character.
+	Notice time.Duration
+	// Erase is written to erase a mistake. If it's nil, mistakes are erased
+	// with EraseBackspace.
+	Erase []byte
+	// Rand is where randomness comes from. If it's nil, a randomly seeded
+	// source is used.
+	Rand *rand.Rand
+}
+
+// Ways to erase a typo.
+var (
+	// back up, cover the mistake with a space, and back up again
+	EraseBackspace = []byte("\b \b")
+	// move the cursor left and erase to the end of the line
+	EraseCSI = []byte("\x1b[D\x1b[K")
+)
+
+// WithTypos makes a Writer make mistakes.
+func WithTypos(t Typos) Option {
+	return func(c *config) {
+		if t.Layout == nil {
+			t.Layout = QWERTY
+		}
+		if t.Erase == nil {
+			t.Erase = EraseBackspace
+		}
+		if t.Rand == nil {
+			t.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+		}
+		c.typos = &t
+	}
+}
+
+// maybe make a mistake before writing token
+func (w *Writer) maybeTypo(token []byte) error {
+	t := w.typos
+	if t == nil || t.Rate <= 0 || w.skipped() {
+		return nil
+	}
+
+	rate := t.Rate
+	if t.Ramp > 0 && w.pos.Rune < t.Ramp {
+		rate *= float64(w.pos.Rune) / float64(t.Ramp)
+	}
+
+	r, size := utf8.DecodeRune(token)
+	if size != len(token) || !unicode.IsLetter(r) || t.Rand.Float64() >= rate {
+		return nil
+	}
+	wrong, ok := t.mistake(r)
+	if !ok {
+		return nil
+	}
+
+	speed := w.Multiplier()
+	mistake := []byte(string(wrong))
+	mistakeDelay := time.Duration(float64(delayAt(w.patience, wrong, w.pos))/speed) + t.Notice
+	w.watched(mistake, mistakeDelay)
+	if err := w.emit(mistake, mistakeDelay); err != nil {
+		return err
+	}
+
+	eraseDelay := time.Duration(float64(delayAt(w.patience, '\b', w.pos)) / speed)
+	w.watched(t.Erase, eraseDelay)
+	return w.emit(t.Erase, eraseDelay)
+}
+
+// anyone watching hears about mistakes and erasing them too, even though
+// they don't move the Writer along. only the first rune is waited for.
+func (w *Writer) watched(p []byte, delay time.Duration) {
+	if w.onRune == nil {
+		return
+	}
+	for len(p) > 0 {
+		r, size := decodeRune(p)
+		p = p[size:]
+		w.onRune(r, delay, w.pos.Rune)
+		delay = 0
+	}
+}
+
+// pick the wrong key for r. usually it's a neighbor, but sometimes fingers
+// just slip.
+func (t *Typos) mistake(r rune) (rune, bool) {
+	neighbors := t.Layout.neighbors(r)
+	if len(neighbors) == 0 {
+		return 0, false
+	}
+	return neighbors[t.Rand.Intn(len(neighbors))], true
+}
+
+// the keys right next to r, with the same shift
+func (l *Layout) neighbors(r rune) []rune {
+	k, ok := l.keys[r]
+	if !ok {
+		return nil
+	}
+
+	var neighbors []rune
+	for other, o := range l.keys {
+		if other == r || o.shifted != k.shifted || !unicode.IsLetter(other) {
+			continue
+		}
+		if math.Hypot(o.x-k.x, o.y-k.y) <= 1.3 {
+			neighbors = append(neighbors, other)
+		}
+	}
+	// map iteration order isn't worth being random about
+	sort.Slice(neighbors, func(i, j int) bool { return neighbors[i] < neighbors[j] })
+	return neighbors
+}
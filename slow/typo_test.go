@@ -0,0 +1,60 @@
+package slow_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+)
+
+func TestTypos(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name  string
+		typos slow.Typos
+		input string
+		// the wrong key comes first, and then everything after it
+		wrong bool
+		after string
+	}{
+		{"never", slow.Typos{Rate: 0}, "f", false, "f"},
+		{"always", slow.Typos{Rate: 1}, "f", true, "\b \bf"},
+		{"not a letter", slow.Typos{Rate: 1}, "1", false, "1"},
+		{"erase", slow.Typos{Rate: 1, Erase: slow.EraseCSI}, "f", true, "\x1b[D\x1b[Kf"},
+		// nothing's wrong at first when getting sloppy takes a while
+		{"ramp", slow.Typos{Rate: 1, Ramp: 10}, "f", false, "f"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.typos.Rand = rand.New(rand.NewSource(1))
+			tt.typos.Notice = 500 * ms
+
+			var watched []rune
+			onRune := func(r rune, _ time.Duration, _ int) { watched = append(watched, r) }
+			got, sleeps := play(t, []string{tt.input}, slow.WithPatience(codePoints), slow.WithTypos(tt.typos), slow.WithOnRune(onRune))
+
+			// anyone watching sees the mistakes too
+			if string(watched) != got {
+				t.Errorf("watched %q, but wrote %q", string(watched), got)
+			}
+
+			want := []time.Duration{time.Duration(tt.input[0]) * ms}
+			if tt.wrong {
+				// a neighbor on the same row, or the one above or below
+				wrong := []rune(got)[0]
+				if !strings.ContainsRune("cdgrtv", wrong) {
+					t.Fatalf("mistyped f as %q", wrong)
+				}
+				got = strings.TrimPrefix(got, string(wrong))
+				want = []time.Duration{time.Duration(wrong)*ms + 500*ms, '\b' * ms, 'f' * ms}
+			}
+			if got != tt.after {
+				t.Errorf("wrote %q, want %q", got, tt.after)
+			}
+			checkSleeps(t, sleeps, want)
+		})
+	}
+}
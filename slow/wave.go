@@ -0,0 +1,47 @@
+package slow
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// A Wave smoothly speeds a Writer up and slows it back down, over and over.
+// Every Period, the multiplier goes from 1 up to Amplitude, back down through
+// 1 to 1/Amplitude, and back up to 1 again.
+type Wave struct {
+	Period    time.Duration
+	Amplitude float64
+}
+
+// Multiplier returns the multiplier elapsed after the Wave starts.
+func (wave Wave) Multiplier(elapsed time.Duration) float64 {
+	if wave.Period <= 0 || wave.Amplitude <= 0 {
+		return 1
+	}
+	phase := 2 * math.Pi * float64(elapsed%wave.Period) / float64(wave.Period)
+	return math.Pow(wave.Amplitude, math.Sin(phase))
+}
+
+// Play keeps changing w's multiplier to ride the Wave, starting now, until ctx
+// is done. It changes the multiplier often enough that the changes look
+// smooth. Time is kept by clock, or by the system clock if clock is nil.
+func (wave Wave) Play(ctx context.Context, w interface{ SetMultiplier(m float64) }, clock Clock) error {
+	if clock == nil {
+		clock = realClock{}
+	}
+
+	const minStep = 10 * time.Millisecond
+	step := wave.Period / 64
+	if step < minStep {
+		step = minStep
+	}
+
+	start := clock.Now()
+	for {
+		w.SetMultiplier(wave.Multiplier(clock.Now().Sub(start)))
+		if err := clock.Sleep(ctx, step); err != nil {
+			return err
+		}
+	}
+}
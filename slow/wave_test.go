@@ -0,0 +1,92 @@
+package slow_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+	"github.com/blinsay/aslap/slow/slowtest"
+)
+
+func TestWaveMultiplier(t *testing.T) {
+	wave := slow.Wave{Period: 4 * time.Second, Amplitude: 2}
+	tests := []struct {
+		name    string
+		wave    slow.Wave
+		elapsed time.Duration
+		want    float64
+	}{
+		{"start", wave, 0, 1},
+		{"fastest", wave, time.Second, 2},
+		{"halfway", wave, 2 * time.Second, 1},
+		{"slowest", wave, 3 * time.Second, 0.5},
+		{"over and over", wave, 5 * time.Second, 2},
+		{"no period", slow.Wave{Amplitude: 2}, time.Second, 1},
+		{"no amplitude", slow.Wave{Period: time.Second}, 250 * time.Millisecond, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.wave.Multiplier(tt.elapsed); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Multiplier(%v) = %v, want %v", tt.elapsed, got, tt.want)
+			}
+		})
+	}
+}
+
+// remembers every multiplier it's given, and gives up after enough of them
+type multiplied struct {
+	got    []float64
+	enough int
+	stop   context.CancelFunc
+}
+
+func (m *multiplied) SetMultiplier(x float64) {
+	m.got = append(m.got, x)
+	if len(m.got) == m.enough {
+		m.stop()
+	}
+}
+
+func TestWavePlay(t *testing.T) {
+	tests := []struct {
+		name   string
+		period time.Duration
+		step   time.Duration
+	}{
+		{"period", 6400 * time.Millisecond, 100 * time.Millisecond},
+		// changing more often than this doesn't look any smoother
+		{"short period", 100 * time.Millisecond, 10 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			clock := slowtest.NewClock(time.Unix(0, 0))
+			w := &multiplied{enough: 20, stop: cancel}
+
+			wave := slow.Wave{Period: tt.period, Amplitude: 2}
+			if err := wave.Play(ctx, w, clock); err != context.Canceled {
+				t.Fatalf("Play returned %v, want %v", err, context.Canceled)
+			}
+
+			sleeps := clock.Sleeps()
+			if len(sleeps) != 19 {
+				t.Fatalf("slept %d times, want 19", len(sleeps))
+			}
+			for _, d := range sleeps {
+				if d != tt.step {
+					t.Fatalf("slept %v, want steps of %v", sleeps, tt.step)
+				}
+			}
+			for i, got := range w.got {
+				if want := wave.Multiplier(time.Duration(i) * tt.step); math.Abs(got-want) > 1e-9 {
+					t.Fatalf("multiplier %d was %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+var heartbeat *time.Duration
+
+func httpFlags() {
+	heartbeat = flag.Duration("heartbeat", 15*time.Second, "with server-sent events, send a comment this often so nobody thinks the stream died while it's waiting")
+}
+
+// whether r is an EventSource, or anything else that'd rather have events
+func wantsEvents(r *http.Request) bool {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		if i := strings.Index(accept, ";"); i >= 0 {
+			accept = accept[:i]
+		}
+		if strings.TrimSpace(accept) == "text/event-stream" {
+			return true
+		}
+	}
+	return false
+}
+
+// send the show as server-sent events, one for every token, with heartbeats
+// in between. once it's over, an end event says so, since an EventSource
+// would otherwise reconnect and start watching all over again.
+func serveEvents(rw http.ResponseWriter, r *http.Request, sh *show) {
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		http.Error(rw, "can't stream events over this connection", http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+
+	events := &events{rw: rw, flusher: flusher}
+	if err := events.send([]byte(": as slow as possible\n\n")); err != nil {
+		return
+	}
+
+	// nothing can be sent once the handler's returned, so it waits for the
+	// heartbeat to stop first
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	defer wg.Wait()
+	defer close(done)
+	if *heartbeat > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ticker := time.NewTicker(*heartbeat)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					if events.send([]byte(": heartbeat\n\n")) != nil {
+						return
+					}
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	if sh.play(r.Context(), events, func() error { return nil }) == nil {
+		events.send([]byte("event: end\ndata:\n\n"))
+	}
+}
+
+// every Write is an event of its own
+type events struct {
+	mu      sync.Mutex
+	rw      http.ResponseWriter
+	flusher http.Flusher
+}
+
+func (e *events) Write(p []byte) (int, error) {
+	// a line of data can't have a line break in it, so every line gets one of
+	// its own and they're put back together on the other side. there's no way
+	// to send a \r at all.
+	var event bytes.Buffer
+	for _, line := range bytes.Split(bytes.Replace(p, []byte("\r"), nil, -1), []byte("\n")) {
+		event.WriteString("data: ")
+		event.Write(line)
+		event.WriteString("\n")
+	}
+	event.WriteString("\n")
+
+	if err := e.send(event.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (e *events) send(p []byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, err := e.rw.Write(p); err != nil {
+		return err
+	}
+	e.flusher.Flush()
+	return nil
+}
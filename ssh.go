@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/text/transform"
+)
+
+var hostKey *string
+
+func sshFlags() {
+	hostKey = flag.String("host-key", "", "a private key file to use as the ssh host key. without one, a new key is made up every time")
+}
+
+// anyone can log in as anyone, without a password, and every session gets the
+// whole show
+func serveSSH(ctx context.Context, l net.Listener, sh *show) error {
+	signer, err := sshSigner(*hostKey)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "host key: %s\n", ssh.FingerprintSHA256(signer.PublicKey()))
+
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(signer)
+
+	return each(ctx, l, func(ctx context.Context, conn net.Conn) {
+		sconn, chans, reqs, err := ssh.NewServerConn(conn, config)
+		if err != nil {
+			return
+		}
+		defer sconn.Close()
+		go ssh.DiscardRequests(reqs)
+
+		// nobody gets to stick around once it's time to go
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			<-ctx.Done()
+			sconn.Close()
+		}()
+
+		var wg sync.WaitGroup
+		defer wg.Wait()
+		for newChannel := range chans {
+			if newChannel.ChannelType() != "session" {
+				newChannel.Reject(ssh.UnknownChannelType, "there's nothing here but the show")
+				continue
+			}
+			ch, reqs, err := newChannel.Accept()
+			if err != nil {
+				continue
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				session(ctx, ch, reqs, sh)
+			}()
+		}
+	})
+}
+
+// play the show once a session asks for a shell or a command, whichever it
+// is. typing ^C or ^D at a terminal hangs up early.
+func session(ctx context.Context, ch ssh.Channel, reqs <-chan *ssh.Request, sh *show) {
+	defer ch.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	start := make(chan bool, 1)
+	go func() {
+		pty := false
+		for req := range reqs {
+			switch req.Type {
+			case "pty-req":
+				pty = true
+				req.Reply(true, nil)
+			case "window-change":
+				// everything's written as a stream, so there's nothing to
+				// redraw at a new size
+			case "env":
+				req.Reply(true, nil)
+			case "shell", "exec":
+				req.Reply(true, nil)
+				select {
+				case start <- pty:
+				default:
+				}
+			default:
+				req.Reply(false, nil)
+			}
+		}
+		// the session's over, one way or another
+		cancel()
+	}()
+	go func() {
+		buf := make([]byte, 256)
+		for {
+			n, err := ch.Read(buf)
+			for _, b := range buf[:n] {
+				if b == 3 || b == 4 {
+					cancel()
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	var pty bool
+	select {
+	case pty = <-start:
+	case <-ctx.Done():
+		return
+	}
+
+	dst := transform.NewWriter(ch, transform.Nop)
+	if pty {
+		// a terminal on the other end needs to be told to go back to the
+		// start of every line
+		dst = transform.NewWriter(ch, transform.Chain(lfOnly{}, crlf{}))
+	}
+
+	status := struct{ Status uint32 }{0}
+	if err := sh.play(ctx, dst, nil); err != nil {
+		status.Status = 1
+	}
+	dst.Close()
+	ch.SendRequest("exit-status", false, ssh.Marshal(&status))
+}
+
+// the host key from a file, or a new one made up on the spot
+func sshSigner(path string) (ssh.Signer, error) {
+	if path != "" {
+		pem, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return ssh.ParsePrivateKey(pem)
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return ssh.NewSignerFromKey(key)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+
+	"golang.org/x/text/transform"
+)
+
+// just enough of telnet (RFC 854) to get out of the way
+const (
+	iac  = 255
+	will = 251
+
+	optEcho            = 1
+	optSuppressGoAhead = 3
+)
+
+// every connection is told the server will echo and won't send go-aheads,
+// which is the usual way of asking a client not to echo anything typed or
+// wait for lines, and then gets the whole show
+func serveTelnet(ctx context.Context, l net.Listener, sh *show) error {
+	return each(ctx, l, func(ctx context.Context, conn net.Conn) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		// nothing anyone says matters, but hanging up means they're gone
+		go func() {
+			io.Copy(ioutil.Discard, conn)
+			cancel()
+		}()
+
+		negotiate := []byte{
+			iac, will, optEcho,
+			iac, will, optSuppressGoAhead,
+		}
+		if _, err := conn.Write(negotiate); err != nil {
+			return
+		}
+		sh.play(ctx, transform.NewWriter(conn, &nvt{}), nil)
+	})
+}
+
+// the network virtual terminal ends every line with \r\n, and sends a literal
+// 255 twice so it isn't mistaken for a command
+type nvt struct {
+	cr bool
+}
+
+func (t *nvt) Reset() {
+	t.cr = false
+}
+
+func (t *nvt) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for ; nSrc < len(src); nSrc++ {
+		b := src[nSrc]
+
+		var escape byte
+		switch {
+		case b == '\n' && !t.cr:
+			escape = '\r'
+		case b == iac:
+			escape = iac
+		}
+
+		need := 1
+		if escape != 0 {
+			need = 2
+		}
+		if nDst+need > len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		if escape != 0 {
+			dst[nDst] = escape
+			nDst++
+		}
+		dst[nDst] = b
+		nDst++
+		t.cr = b == '\r'
+	}
+	return nDst, nSrc, nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+var (
+	templating = flag.Bool("template", false, "read everything first as a Go text/template, and play what it renders. {{.NAME}} is a -set value or an environment variable")
+	values     = setFlag{}
+)
+
+func init() {
+	flag.Var(values, "set", "set a value for -template, like name=value. can be given more than once")
+}
+
+// expand everything in src as a template. -set values win over the
+// environment, and anything missing from both is an error instead of a blank.
+func expand(src io.Reader) (io.Reader, error) {
+	text, err := ioutil.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := template.New("input").Option("missingkey=error").Parse(string(text))
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]string{}
+	for _, env := range os.Environ() {
+		if i := strings.Index(env, "="); i > 0 {
+			data[env[:i]] = env[i+1:]
+		}
+	}
+	for k, v := range values {
+		data[k] = v
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
+
+// name=value, as many times as it takes
+type setFlag map[string]string
+
+func (s setFlag) String() string {
+	var pairs []string
+	for k, v := range s {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (s setFlag) Set(pair string) error {
+	i := strings.Index(pair, "=")
+	if i < 1 {
+		return fmt.Errorf("%q isn't name=value", pair)
+	}
+	s[pair[:i]] = pair[i+1:]
+	return nil
+}
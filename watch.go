@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/blinsay/aslap/slow"
+	"github.com/fsnotify/fsnotify"
+)
+
+// editors tend to change a file a few times in a row when they save it
+const settle = 100 * time.Millisecond
+
+// clear the screen and put the cursor at the top
+var clearScreen = []byte("\x1b[H\x1b[2J")
+
+// play a file over and over, starting over from the top every time it changes.
+// watch never returns unless something goes wrong.
+func watch(name string, dst io.Writer, patience slow.Patience, opts []slow.Option) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	// watch the whole directory, since plenty of editors save by replacing the
+	// file instead of writing to it
+	if err := watcher.Add(filepath.Dir(name)); err != nil {
+		return err
+	}
+
+	for {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			done <- play(ctx, name, dst, patience, opts)
+		}()
+
+		if err := waitForChange(watcher, name, done); err != nil {
+			cancel()
+			return err
+		}
+		cancel()
+		<-done
+	}
+}
+
+// play a file once, from the top of a clear screen
+func play(ctx context.Context, name string, dst io.Writer, patience slow.Patience, opts []slow.Option) error {
+	if r, ok := patience.(slow.Resetter); ok {
+		r.Reset()
+	}
+	if _, err := dst.Write(clearScreen); err != nil {
+		return err
+	}
+
+	f, err := openInput(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := slow.New(dst, append(opts, slow.WithContext(ctx))...)
+	if _, err := io.Copy(w, f); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// wait until name changes. anything that goes wrong playing it along the way
+// is worth mentioning, but not worth stopping for.
+func waitForChange(watcher *fsnotify.Watcher, name string, done chan error) error {
+	changes := fsnotify.Write | fsnotify.Create | fsnotify.Rename
+	for {
+		select {
+		case event := <-watcher.Events:
+			if filepath.Clean(event.Name) != filepath.Clean(name) || event.Op&changes == 0 {
+				continue
+			}
+			settleDown(watcher)
+			return nil
+		case err := <-watcher.Errors:
+			return err
+		case err := <-done:
+			if err != nil && err != context.Canceled {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			// put it back so there's something to wait for later
+			done <- nil
+			done = nil
+		}
+	}
+}
+
+// ignore everything that happens for a little while
+func settleDown(watcher *fsnotify.Watcher) {
+	timeout := time.After(settle)
+	for {
+		select {
+		case <-watcher.Events:
+		case <-timeout:
+			return
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// just enough of RFC 6455 to send a message for every token
+const (
+	wsText   = 0x1
+	wsBinary = 0x2
+	wsClose  = 0x8
+	wsPing   = 0x9
+	wsPong   = 0xa
+
+	wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	// nobody has anything that big to say to a show
+	wsMaxPayload = 1 << 20
+)
+
+// whether r is asking to be upgraded to a websocket
+func isWebSocket(r *http.Request) bool {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return false
+	}
+	for _, v := range strings.Split(r.Header.Get("Connection"), ",") {
+		if strings.EqualFold(strings.TrimSpace(v), "upgrade") {
+			return true
+		}
+	}
+	return false
+}
+
+// upgrade to a websocket and send the show as a message for every token. when
+// it's over, or when it's time to go, the socket is closed properly.
+func serveWebSocket(rw http.ResponseWriter, r *http.Request, sh *show) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" || r.Header.Get("Sec-WebSocket-Version") != "13" {
+		http.Error(rw, "not a websocket handshake anyone knows", http.StatusBadRequest)
+		return
+	}
+	hijacker, ok := rw.(http.Hijacker)
+	if !ok {
+		http.Error(rw, "can't upgrade this connection", http.StatusInternalServerError)
+		return
+	}
+	conn, brw, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	accept := sha1.Sum([]byte(key + wsGUID))
+	brw.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+	brw.WriteString("Upgrade: websocket\r\n")
+	brw.WriteString("Connection: Upgrade\r\n")
+	brw.WriteString("Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(accept[:]) + "\r\n\r\n")
+	if err := brw.Flush(); err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	ws := &webSocket{conn: conn}
+	go func() {
+		ws.listen(brw.Reader)
+		cancel()
+	}()
+
+	sh.play(ctx, ws, func() error { return nil })
+	ws.frame(wsClose, []byte{0x03, 0xe8}) // 1000, all done
+}
+
+// the server's end of a websocket. every Write is a message of its own.
+//
+// text messages have to be valid UTF-8, and a token doesn't have to be. going
+// a byte at a time splits runes, and -binary passes through bytes that were
+// never UTF-8 in the first place, so anything that isn't valid UTF-8 is sent
+// as binary instead.
+type webSocket struct {
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func (ws *webSocket) Write(p []byte) (int, error) {
+	opcode := byte(wsText)
+	if !utf8.Valid(p) {
+		opcode = wsBinary
+	}
+	if err := ws.frame(opcode, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// server frames are never masked or fragmented
+func (ws *webSocket) frame(opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n < 126:
+		header = append(header, byte(n))
+	case n <= 0xffff:
+		header = append(header, 126, byte(n>>8), byte(n))
+	default:
+		header = append(header, 127)
+		for shift := 56; shift >= 0; shift -= 8 {
+			header = append(header, byte(uint64(n)>>uint(shift)))
+		}
+	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	_, err := ws.conn.Write(append(header, payload...))
+	return err
+}
+
+// read everything the client sends until it wants to close, answering pings
+// along the way. nothing else it says matters.
+func (ws *webSocket) listen(r *bufio.Reader) {
+	for {
+		opcode, payload, err := readFrame(r)
+		if err != nil {
+			return
+		}
+		switch opcode {
+		case wsClose:
+			return
+		case wsPing:
+			if ws.frame(wsPong, payload) != nil {
+				return
+			}
+		}
+	}
+}
+
+func readFrame(r *bufio.Reader) (opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	opcode = header[0] & 0x0f
+
+	// lengths past 125 come after the header, big-endian
+	n := uint64(header[1] & 0x7f)
+	extended := 0
+	switch n {
+	case 126:
+		extended = 2
+	case 127:
+		extended = 8
+	}
+	if extended > 0 {
+		size := make([]byte, extended)
+		if _, err := io.ReadFull(r, size); err != nil {
+			return 0, nil, err
+		}
+		n = 0
+		for _, b := range size {
+			n = n<<8 | uint64(b)
+		}
+	}
+	if n > wsMaxPayload {
+		return 0, nil, errors.New("websocket frame too big")
+	}
+
+	var mask [4]byte
+	if header[1]&0x80 != 0 {
+		if _, err := io.ReadFull(r, mask[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+	payload = make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return opcode, payload, nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bufio"
+	"net"
+	"testing"
+)
+
+func TestWebSocketWrite(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		opcode byte
+	}{
+		{"text", "hi", wsText},
+		{"multibyte", "世", wsText},
+		// a rune split in half can't be text
+		{"half a rune", "\xe4\xb8", wsBinary},
+		{"not utf-8", "\xff", wsBinary},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := net.Pipe()
+			defer server.Close()
+			defer client.Close()
+
+			ws := &webSocket{conn: server}
+			go ws.Write([]byte(tt.token))
+
+			opcode, payload, err := readFrame(bufio.NewReader(client))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if opcode != tt.opcode || string(payload) != tt.token {
+				t.Errorf("got opcode %#x and %q, want %#x and %q", opcode, payload, tt.opcode, tt.token)
+			}
+		})
+	}
+}